@@ -4,104 +4,134 @@ import (
 	"database/sql"
 	"fmt"
 	"reflect"
+	"strings"
 
+	"github.com/dev-cloverlab/carpenter/dialect"
 	"github.com/dev-cloverlab/carpenter/dialect/mysql"
 )
 
-func Build(db *sql.DB, old, new *mysql.Table, withDrop bool) (queries []string, err error) {
+func Build(db *sql.DB, d dialect.Dialect, old, new *mysql.Table, withDrop, detectRename bool) (queries []string, err error) {
 	if old == nil && new == nil {
 		return queries, fmt.Errorf("err: Both pointer of the specified new and old is nil.")
 	}
-	if old != nil && new != nil && old.TableName != new.TableName {
-		return queries, fmt.Errorf("err: Table name of the specified new and old is a difference")
+	if err := validateTableName(old, new, detectRename); err != nil {
+		return queries, err
 	}
 	if reflect.DeepEqual(old, new) {
 		return queries, nil
 	}
-	if q := willCreate(old, new); len(q) > 0 {
-		queries = append(queries, q)
+	if q := willCreate(d, old, new); len(q) > 0 {
+		queries = append(queries, q...)
 	}
 	if withDrop {
-		if q := willDrop(old, new); len(q) > 0 {
+		if q := willDrop(d, old, new); len(q) > 0 {
 			queries = append(queries, q)
 		}
 	}
-	if q := willAlter(old, new); len(q) > 0 {
+	if old == nil || new == nil {
+		return queries, nil
+	}
+	if q := willRenameTable(d, old, new); len(q) > 0 {
 		queries = append(queries, q)
+		old = old.WithTableName(new.TableName)
+	}
+	renamedColumns := getRenamedColumns(old, new, detectRename)
+	old = old.WithRenamedColumns(getRenamedColumnNames(renamedColumns))
+	renamedIndices := getRenamedIndices(old, new, detectRename)
+	old = old.WithRenamedIndices(getRenamedKeyNames(renamedIndices))
+	if alter := willAlter(old, new, renamedColumns, renamedIndices, withDrop); alter != nil {
+		q, err := d.ToAlterSQL(new, alter)
+		if err != nil {
+			return queries, err
+		}
+		queries = append(queries, q...)
 	}
 	return queries, nil
 }
 
-func willCreate(old, new *mysql.Table) string {
+func willCreate(d dialect.Dialect, old, new *mysql.Table) []string {
 	if old == nil && new != nil {
-		return new.ToCreateSQL()
+		return d.ToCreateSQL(new)
 	}
-	return ""
+	return []string{}
 }
 
-func willDrop(old, new *mysql.Table) string {
+func willDrop(d dialect.Dialect, old, new *mysql.Table) string {
 	if old != nil && new == nil {
-		return old.ToDropSQL()
+		return d.ToDropSQL(old)
 	}
 	return ""
 }
 
-func willAlterTableCharacterSet(old, new *mysql.Table) []string {
-	if old == nil || new == nil {
-		return []string{}
-	}
-
-	alter := []string{}
+func willAlterTableCharacterSet(old, new *mysql.Table) bool {
 	if old.GetCharset() != new.GetCharset() {
-		alter = append(alter, new.ToConvertCharsetSQL())
 		old.TableCollation = new.TableCollation
+		return true
 	}
-	return alter
+	return false
 }
 
-func willAlterColumnCharacterSet(old, new *mysql.Table) []string {
-	if old == nil || new == nil {
-		return []string{}
-	}
-
+func willAlterColumnCharacterSet(old, new *mysql.Table, renamedColumns map[string]*mysql.Column) mysql.Columns {
 	newCols := new.Columns.GroupByColumnName()
 	oldCols := old.Columns.GroupByColumnName()
-	sqls := []string{}
+	cols := mysql.Columns{}
 	for _, colName := range new.Columns.GetSortedColumnNames() {
 		if _, ok := oldCols[colName]; !ok {
 			continue
 		}
+		if _, ok := renamedColumns[colName]; ok {
+			continue
+		}
 		newCol := newCols[colName]
 		oldCol := oldCols[colName]
 		if !newCol.CharacterSetName.Valid || (oldCol.CompareCharacterSet(newCol) && oldCol.CompareCollation(newCol)) {
 			continue
 		}
 		oldCols[colName].CollationName = newCol.CollationName
-		sqls = append(sqls, newCol.ToModifyCharsetSQL())
+		cols = append(cols, newCol)
 	}
-	return sqls
+	return cols
 }
 
-func willAlter(old, new *mysql.Table) string {
-	if old == nil || new == nil {
-		return ""
-	}
+// willAlter returns the differences of the table.
+// The partitions are dropped only when withDrop is true, because their rows are deleted with them.
+func willAlter(old, new *mysql.Table, renamedColumns map[string]*mysql.Column, renamedIndices map[string]mysql.Index, withDrop bool) *mysql.Alter {
 	if reflect.DeepEqual(old, new) {
-		return ""
+		return nil
 	}
 
-	alter := []string{}
-	alter = append(alter, willAlterTableCharacterSet(old, new)...)
-	alter = append(alter, willAlterColumnCharacterSet(old, new)...)
-	alter = append(alter, willDropIndex(old, new)...)
-	alter = append(alter, willDropColumn(old, new)...)
-	alter = append(alter, willAddColumn(old, new)...)
-	alter = append(alter, willAddIndex(old, new)...)
-	alter = append(alter, willModifyColumn(old, new)...)
-	return new.ToAlterSQL(alter, willModifyPartition(old, new))
+	alter := &mysql.Alter{}
+	alter.ConvertCharset = willAlterTableCharacterSet(old, new)
+	alter.ModifyCharsetColumns = willAlterColumnCharacterSet(old, new, renamedColumns)
+	alter.DropForeignKeys = willDropForeignKey(old, new)
+	alter.DropCheckConstraints = willDropCheckConstraint(old, new)
+	alter.DropIndices = willDropIndex(old, new)
+	recreated := willRecreateColumn(old, new, renamedColumns)
+	alter.DropColumns = append(willDropColumn(old, new), recreated...)
+	alter.ChangeColumns = willChangeColumn(new, renamedColumns)
+	alter.RenameIndices = willRenameIndex(new, renamedIndices)
+	alter.AddColumns = append(willAddColumn(old, new), recreated...)
+	alter.AddIndices = willAddIndex(old, new)
+	alter.AlterIndices = willAlterIndex(old, new)
+	alter.ModifyColumns = willModifyColumn(old, new, renamedColumns)
+	alter.ModifyComments = willModifyComment(old, new)
+	alter.AddForeignKeys = willAddForeignKey(old, new)
+	alter.AddCheckConstraints = willAddCheckConstraint(old, new)
+	alter.AlterCheckConstraints = willAlterCheckConstraint(old, new)
+	recreateDependencies(alter, old, new, recreated)
+	alter.Partitions = willModifyPartition(old, new)
+	alter.RemovePartitioning = willRemovePartitioning(old, new)
+	if changes := getPartitionChanges(old, new); changes != nil {
+		if withDrop {
+			alter.DropPartitions = changes.drops
+		}
+		alter.ReorganizePartitions = changes.reorganizes
+		alter.AddPartitions = changes.adds
+	}
+	return alter
 }
 
-func willAddColumn(old, new *mysql.Table) []string {
+func willAddColumn(old, new *mysql.Table) mysql.Columns {
 	cols := mysql.Columns{}
 	for _, column := range new.Columns {
 		if old.Columns.Contains(column) {
@@ -109,10 +139,10 @@ func willAddColumn(old, new *mysql.Table) []string {
 		}
 		cols = append(cols, column)
 	}
-	return cols.ToAddSQL(new.Columns)
+	return cols
 }
 
-func willDropColumn(old, new *mysql.Table) []string {
+func willDropColumn(old, new *mysql.Table) mysql.Columns {
 	cols := mysql.Columns{}
 	for _, column := range old.Columns {
 		if new.Columns.Contains(column) {
@@ -120,77 +150,403 @@ func willDropColumn(old, new *mysql.Table) []string {
 		}
 		cols = append(cols, column)
 	}
-	return cols.ToDropSQL()
+	return cols
+}
+
+// willRecreateColumn returns the new columns which become or cease to be virtual generated columns.
+// They are dropped and added again because MySQL can not modify them in place.
+func willRecreateColumn(old, new *mysql.Table, renamedColumns map[string]*mysql.Column) mysql.Columns {
+	oldCols := old.Columns.GroupByColumnName()
+	cols := mysql.Columns{}
+	for _, column := range new.Columns {
+		oldCol, ok := oldCols[column.ColumnName]
+		if !ok {
+			continue
+		}
+		if _, ok := renamedColumns[column.ColumnName]; ok {
+			continue
+		}
+		if isVirtual(oldCol) != isVirtual(column) {
+			cols = append(cols, column)
+		}
+	}
+	return cols
+}
+
+// recreateDependencies drops and adds again the indices, the foreign keys and the CHECK constraints which refer to the recreated columns,
+// because dropping a column removes it from the indices and fails with the constraints referring to it.
+func recreateDependencies(alter *mysql.Alter, old, new *mysql.Table, recreated mysql.Columns) {
+	if len(recreated) <= 0 {
+		return
+	}
+	names := map[string]struct{}{}
+	for _, column := range recreated {
+		names[column.ColumnName] = struct{}{}
+	}
+	refers := func(columnNames []string, expressions []string) bool {
+		for _, name := range columnNames {
+			if _, ok := names[name]; ok {
+				return true
+			}
+		}
+		for _, expression := range expressions {
+			for name := range names {
+				if strings.Contains(expression, mysql.Quote(name)) {
+					return true
+				}
+			}
+		}
+		return false
+	}
+
+	newIndicesMap := new.Indices.GroupByKeyName()
+	oldIndicesMap := old.Indices.GroupByKeyName()
+	for _, keyName := range old.Indices.GetSortedKeys() {
+		newIndices, ok := newIndicesMap[keyName]
+		if !ok {
+			// dropped by willDropIndex
+			continue
+		}
+		columnNames := []string{}
+		expressions := []string{}
+		for _, index := range oldIndicesMap[keyName] {
+			for _, info := range index {
+				columnNames = append(columnNames, info.ColumnName)
+				expressions = append(expressions, info.Expression.String)
+			}
+		}
+		if !refers(columnNames, expressions) {
+			continue
+		}
+		adds := []*mysql.AddIndex{}
+		for _, add := range alter.AddIndices {
+			if add.Indices[0].GetKeyName() != keyName {
+				adds = append(adds, add)
+			}
+		}
+		alter.AddIndices = append(adds, &mysql.AddIndex{Indices: newIndices})
+		alters := mysql.Indices{}
+		for _, index := range alter.AlterIndices {
+			if index.GetKeyName() != keyName {
+				alters = append(alters, index)
+			}
+		}
+		alter.AlterIndices = alters
+		alter.DropIndices = append(alter.DropIndices, oldIndicesMap[keyName]...)
+	}
+
+	newFKMap := new.ForeignKeys.GroupByConstraintName()
+	oldFKMap := old.ForeignKeys.GroupByConstraintName()
+	dropFKMap := alter.DropForeignKeys.GroupByConstraintName()
+	for _, name := range old.ForeignKeys.GetSortedConstraintNames() {
+		if _, ok := dropFKMap[name]; ok || !refers(oldFKMap[name].ColumnNames, nil) {
+			// dropped and added again by willDropForeignKey and willAddForeignKey
+			continue
+		}
+		alter.DropForeignKeys = append(alter.DropForeignKeys, oldFKMap[name])
+		alter.AddForeignKeys = append(alter.AddForeignKeys, newFKMap[name])
+	}
+
+	newCheckMap := new.CheckConstraints.GroupByConstraintName()
+	oldCheckMap := old.CheckConstraints.GroupByConstraintName()
+	dropCheckMap := alter.DropCheckConstraints.GroupByConstraintName()
+	for _, name := range old.CheckConstraints.GetSortedConstraintNames() {
+		if _, ok := dropCheckMap[name]; ok || !refers(nil, []string{oldCheckMap[name].CheckClause}) {
+			// dropped and added again by willDropCheckConstraint and willAddCheckConstraint
+			continue
+		}
+		alter.DropCheckConstraints = append(alter.DropCheckConstraints, oldCheckMap[name])
+		alter.AddCheckConstraints = append(alter.AddCheckConstraints, newCheckMap[name])
+		alters := mysql.CheckConstraints{}
+		for _, check := range alter.AlterCheckConstraints {
+			if check.ConstraintName != name {
+				alters = append(alters, check)
+			}
+		}
+		alter.AlterCheckConstraints = alters
+	}
+}
+
+func isVirtual(column *mysql.Column) bool {
+	return column.GenerationType() == "virtual"
 }
 
-func willModifyColumn(old, new *mysql.Table) []string {
+func willModifyColumn(old, new *mysql.Table, renamedColumns map[string]*mysql.Column) mysql.Columns {
 	newCols := new.Columns.GroupByColumnName()
 	oldCols := old.Columns.GroupByColumnName()
-	sqls := []string{}
+	cols := mysql.Columns{}
 	for _, colName := range new.Columns.GetSortedColumnNames() {
 		if _, ok := oldCols[colName]; !ok {
 			continue
 		}
+		if _, ok := renamedColumns[colName]; ok {
+			continue
+		}
 		newCol := newCols[colName]
 		oldCol := oldCols[colName]
+		if isVirtual(oldCol) != isVirtual(newCol) {
+			// recreated by willRecreateColumn
+			continue
+		}
 		oldTableSchema := oldCol.TableSchema
 		oldColumnKey := oldCol.ColumnKey
 		oldPrivileges := oldCol.Privileges
 		oldOrdinalPosition := oldCol.OrdinalPosition
+		oldPreviousNames := oldCol.PreviousNames
 		oldCol.TableSchema = newCol.TableSchema
 		oldCol.ColumnKey = newCol.ColumnKey
 		oldCol.Privileges = newCol.Privileges
 		oldCol.OrdinalPosition = newCol.OrdinalPosition
+		oldCol.PreviousNames = newCol.PreviousNames
 		if !reflect.DeepEqual(oldCol, newCol) {
-			sqls = append(sqls, newCol.ToModifySQL())
+			cols = append(cols, newCol)
 		}
 		oldCol.TableSchema = oldTableSchema
 		oldCol.ColumnKey = oldColumnKey
 		oldCol.Privileges = oldPrivileges
 		oldCol.OrdinalPosition = oldOrdinalPosition
+		oldCol.PreviousNames = oldPreviousNames
 	}
-	return sqls
+	return cols
 }
 
-func willModifyPartition(old, new *mysql.Table) string {
-	if reflect.DeepEqual(old.Partitions, new.Partitions) {
-		return ""
+// willModifyComment returns the new columns whose comment is changed, including the renamed columns.
+// The columns recreated by willRecreateColumn are excluded because they are added with the comment.
+func willModifyComment(old, new *mysql.Table) mysql.Columns {
+	oldCols := old.Columns.GroupByColumnName()
+	cols := mysql.Columns{}
+	for _, newCol := range new.Columns {
+		oldCol, ok := oldCols[newCol.ColumnName]
+		if !ok || isVirtual(oldCol) != isVirtual(newCol) {
+			continue
+		}
+		if oldCol.ColumnComment != newCol.ColumnComment {
+			cols = append(cols, newCol)
+		}
+	}
+	return cols
+}
+
+// willModifyPartition returns the partitions to repartition the table,
+// when the partitions can not be changed in place by getPartitionChanges.
+func willModifyPartition(old, new *mysql.Table) mysql.Partitions {
+	if len(new.Partitions) <= 0 || old.Partitions.ToSQL() == new.Partitions.ToSQL() {
+		return nil
+	}
+	if getPartitionChanges(old, new) != nil {
+		return nil
+	}
+	return new.Partitions
+}
+
+func willRemovePartitioning(old, new *mysql.Table) bool {
+	return len(old.Partitions) > 0 && len(new.Partitions) <= 0
+}
+
+type partitionChanges struct {
+	drops       mysql.Partitions
+	reorganizes []*mysql.ReorganizePartition
+	adds        mysql.Partitions
+}
+
+// getPartitionChanges returns the partitions of RANGE or LIST to add, drop and reorganize,
+// so that the table is not rebuilt entirely. It returns nil when there is no change,
+// or the partitions can not be changed in place, for example the partitioning method is changed
+// or the unchanged partitions are reordered.
+//
+// The unchanged partitions are kept, and the partitions between them are compared in order.
+// The new partitions in place of no old one are added, the old partitions in place of no new one are dropped,
+// and the others are reorganized. The new RANGE partitions before the kept one like MAXVALUE partition
+// are made by reorganizing the kept one, because RANGE partitions can only be added to the end.
+func getPartitionChanges(old, new *mysql.Table) *partitionChanges {
+	if !old.Partitions.EqualScheme(new.Partitions) || old.Partitions[0].IsHashOrKey() || old.Partitions.ToSQL() == new.Partitions.ToSQL() {
+		return nil
+	}
+	method := old.Partitions[0].PartitionMethod
+	isRange := method == mysql.PartitionMethodRangeExpr || method == mysql.PartitionMethodRange
+	oldGroups := old.Partitions.GroupByPartitionName()
+	oldPos := make(map[string]int, len(oldGroups))
+	for i, partitions := range oldGroups {
+		oldPos[partitions[0].PartitionName] = i
 	}
-	if len(new.Partitions) <= 0 {
-		return ""
+
+	changes := &partitionChanges{}
+	prev := -1
+	run := mysql.Partitions{}
+	// flush compares the run of the new partitions with the old partitions before the kept one of next
+	flush := func(next int) bool {
+		between := mysql.Partitions{}
+		for _, partitions := range oldGroups[prev+1 : next] {
+			between = append(between, partitions...)
+		}
+		for _, name := range run.GetPartitionNames() {
+			if pos, ok := oldPos[name]; ok && (pos <= prev || pos >= next) {
+				return false
+			}
+		}
+		switch {
+		case len(run) <= 0 && len(between) <= 0:
+		case len(run) <= 0:
+			changes.drops = append(changes.drops, between...)
+		case len(between) > 0:
+			changes.reorganizes = append(changes.reorganizes, &mysql.ReorganizePartition{Partitions: between, Into: run})
+		case isRange && next < len(oldGroups):
+			into := append(append(mysql.Partitions{}, run...), oldGroups[next]...)
+			changes.reorganizes = append(changes.reorganizes, &mysql.ReorganizePartition{Partitions: oldGroups[next], Into: into})
+		default:
+			changes.adds = append(changes.adds, run...)
+		}
+		run = mysql.Partitions{}
+		return true
+	}
+	for _, partitions := range new.Partitions.GroupByPartitionName() {
+		pos, ok := oldPos[partitions[0].PartitionName]
+		if !ok || oldGroups[pos].ToDefinitionSQL() != partitions.ToDefinitionSQL() {
+			run = append(run, partitions...)
+			continue
+		}
+		if pos <= prev || !flush(pos) {
+			return nil
+		}
+		prev = pos
+	}
+	if !flush(len(oldGroups)) {
+		return nil
 	}
-	return new.Partitions.ToSQL()
+	return changes
 }
 
-func willAddIndex(old, new *mysql.Table) []string {
+func willAddIndex(old, new *mysql.Table) []*mysql.AddIndex {
 	newIndicesMap := new.Indices.GroupByKeyName()
 	oldIndicesMap := old.Indices.GroupByKeyName()
-	sqls := []string{}
+	indices := []*mysql.AddIndex{}
 	for _, keyName := range new.Indices.GetSortedKeys() {
 		if _, ok := oldIndicesMap[keyName]; !ok {
-			sqls = append(sqls, newIndicesMap[keyName].ToAddSQL()...)
+			indices = append(indices, &mysql.AddIndex{Indices: newIndicesMap[keyName]})
 			continue
 		}
 		newIndices := newIndicesMap[keyName]
 		oldIndices := oldIndicesMap[keyName]
-		if reflect.DeepEqual(oldIndices, newIndices) {
+		if reflect.DeepEqual(oldIndices, newIndices.WithoutPreviousKeyNames()) || equalIndexExceptVisibility(oldIndices, newIndices) {
 			continue
 		}
-		sqls = append(sqls, oldIndices.ToDropSQL()...)
-		sqls = append(sqls, newIndices.ToAddSQL()...)
+		indices = append(indices, &mysql.AddIndex{Replace: oldIndices, Indices: newIndices})
 	}
-	return sqls
+	return indices
 }
 
-func willDropIndex(old, new *mysql.Table) []string {
+// willAlterIndex returns the indices whose visibility is only changed,
+// which are altered in place instead of being dropped and added again.
+func willAlterIndex(old, new *mysql.Table) mysql.Indices {
 	newIndicesMap := new.Indices.GroupByKeyName()
 	oldIndicesMap := old.Indices.GroupByKeyName()
-	sqls := []string{}
+	indices := mysql.Indices{}
+	for _, keyName := range new.Indices.GetSortedKeys() {
+		oldIndices, ok := oldIndicesMap[keyName]
+		if !ok {
+			continue
+		}
+		newIndices := newIndicesMap[keyName]
+		if !equalIndexExceptVisibility(oldIndices, newIndices) || oldIndices[0].IsVisible() == newIndices[0].IsVisible() {
+			continue
+		}
+		indices = append(indices, newIndices[0])
+	}
+	return indices
+}
+
+// equalIndexExceptVisibility reports whether both indices are the same except for the visibility,
+// whose empty value of the older definitions equals "YES".
+func equalIndexExceptVisibility(old, new mysql.Indices) bool {
+	if len(old) != 1 || len(new) != 1 {
+		return false
+	}
+	new = new.WithoutPreviousKeyNames()
+	return reflect.DeepEqual(old[0].WithVisibility(true), new[0].WithVisibility(true))
+}
+
+func willDropIndex(old, new *mysql.Table) mysql.Indices {
+	newIndicesMap := new.Indices.GroupByKeyName()
+	oldIndicesMap := old.Indices.GroupByKeyName()
+	indices := mysql.Indices{}
 	for _, keyName := range old.Indices.GetSortedKeys() {
 		if _, ok := newIndicesMap[keyName]; ok {
 			continue
 		}
-		sqls = append(sqls, oldIndicesMap[keyName].ToDropSQL()...)
+		indices = append(indices, oldIndicesMap[keyName]...)
+	}
+	return indices
+}
+
+func willDropForeignKey(old, new *mysql.Table) mysql.ForeignKeys {
+	newFKMap := new.ForeignKeys.GroupByConstraintName()
+	oldFKMap := old.ForeignKeys.GroupByConstraintName()
+	fks := mysql.ForeignKeys{}
+	for _, name := range old.ForeignKeys.GetSortedConstraintNames() {
+		if newFK, ok := newFKMap[name]; ok && newFK.Equal(oldFKMap[name]) {
+			continue
+		}
+		fks = append(fks, oldFKMap[name])
+	}
+	return fks
+}
+
+func willAddForeignKey(old, new *mysql.Table) mysql.ForeignKeys {
+	newFKMap := new.ForeignKeys.GroupByConstraintName()
+	oldFKMap := old.ForeignKeys.GroupByConstraintName()
+	fks := mysql.ForeignKeys{}
+	for _, name := range new.ForeignKeys.GetSortedConstraintNames() {
+		if oldFK, ok := oldFKMap[name]; ok && oldFK.Equal(newFKMap[name]) {
+			continue
+		}
+		fks = append(fks, newFKMap[name])
+	}
+	return fks
+}
+
+// willDropCheckConstraint returns the constraints which are removed or whose clauses are changed.
+// The changed constraints are added again by willAddCheckConstraint.
+func willDropCheckConstraint(old, new *mysql.Table) mysql.CheckConstraints {
+	newCheckMap := new.CheckConstraints.GroupByConstraintName()
+	oldCheckMap := old.CheckConstraints.GroupByConstraintName()
+	checks := mysql.CheckConstraints{}
+	for _, name := range old.CheckConstraints.GetSortedConstraintNames() {
+		if newCheck, ok := newCheckMap[name]; ok && newCheck.EqualClause(oldCheckMap[name]) {
+			continue
+		}
+		checks = append(checks, oldCheckMap[name])
+	}
+	return checks
+}
+
+func willAddCheckConstraint(old, new *mysql.Table) mysql.CheckConstraints {
+	newCheckMap := new.CheckConstraints.GroupByConstraintName()
+	oldCheckMap := old.CheckConstraints.GroupByConstraintName()
+	checks := mysql.CheckConstraints{}
+	for _, name := range new.CheckConstraints.GetSortedConstraintNames() {
+		if oldCheck, ok := oldCheckMap[name]; ok && oldCheck.EqualClause(newCheckMap[name]) {
+			continue
+		}
+		checks = append(checks, newCheckMap[name])
+	}
+	return checks
+}
+
+// willAlterCheckConstraint returns the constraints whose enforcement is only changed.
+// The constraints of the servers which do not have the enforcement like MariaDB are never altered.
+func willAlterCheckConstraint(old, new *mysql.Table) mysql.CheckConstraints {
+	newCheckMap := new.CheckConstraints.GroupByConstraintName()
+	oldCheckMap := old.CheckConstraints.GroupByConstraintName()
+	checks := mysql.CheckConstraints{}
+	for _, name := range new.CheckConstraints.GetSortedConstraintNames() {
+		oldCheck, ok := oldCheckMap[name]
+		if !ok || !oldCheck.EqualClause(newCheckMap[name]) || oldCheck.IsEnforced() == newCheckMap[name].IsEnforced() {
+			continue
+		}
+		if len(oldCheck.Enforced) <= 0 || len(newCheckMap[name].Enforced) <= 0 {
+			continue
+		}
+		checks = append(checks, newCheckMap[name])
 	}
-	return sqls
+	return checks
 }
@@ -37,7 +37,16 @@ func TestMain(m *testing.M) {
 		panic(err)
 	}
 	code := m.Run()
-	_, err = db.Exec("drop table if exists `build_test`")
+	for _, q := range []string{
+		"drop view if exists `build_view`",
+		"drop trigger if exists `build_trigger`",
+		"drop procedure if exists `build_procedure`",
+	} {
+		if _, err := db.Exec(q); err != nil {
+			panic(err)
+		}
+	}
+	_, err = db.Exec("drop table if exists `build_test`, `build_fk_child`, `build_fk_parent`")
 	if err != nil {
 		panic(err)
 	}
@@ -64,7 +73,7 @@ func TestSingleCreate(t *testing.T) {
 			"	key `k2` (`gender`,`country`)\n" +
 			") engine=InnoDB default charset=utf8 ",
 	}
-	actual, err := Build(db, nil, new[0], true)
+	actual, err := Build(db, mysql.Dialect{}, nil, new[0], true, false)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -100,7 +109,7 @@ func TestAlter(t *testing.T) {
 			"	add key `k3` (`gender`),\n" +
 			"	modify `country` tinyint(4) not null \n\t",
 	}
-	actual, err := Build(db, old[0], new[0], true)
+	actual, err := Build(db, mysql.Dialect{}, old[0], new[0], true, false)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -122,7 +131,7 @@ func TestSingleDrop(t *testing.T) {
 	expected := []string{
 		"drop table if exists `build_test`",
 	}
-	actual, err := Build(db, old[0], nil, true)
+	actual, err := Build(db, mysql.Dialect{}, old[0], nil, true, false)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -141,7 +150,7 @@ func TestDateTimeDefaultNull(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	actual, err := Build(db, nil, new[0], true)
+	actual, err := Build(db, mysql.Dialect{}, nil, new[0], true, false)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -152,6 +161,518 @@ func TestDateTimeDefaultNull(t *testing.T) {
 	}
 }
 
+func TestRename(t *testing.T) {
+	old, err := getTables("./_test/table1.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	new, err := getTables("./_test/table6.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{
+		"alter table `build_test` drop `email`,\n" +
+			"	change `name` `nickname` varchar(64) not null ,\n" +
+			"	rename index `name` to `uniq_nickname`,\n" +
+			"	add `mail` varchar(255) not null  after `nickname`\n\t",
+	}
+	actual, err := Build(db, mysql.Dialect{}, old[0], new[0], true, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("err: rename: unexpected SQL returned.\nactual:\n%s\nexpected:\n%s\n", actual, expected)
+	}
+
+	expected = []string{
+		"alter table `build_test` change `name` `nickname` varchar(64) not null ,\n" +
+			"	change `email` `mail` varchar(255) not null ,\n" +
+			"	rename index `name` to `uniq_nickname`\n\t",
+	}
+	actual, err = Build(db, mysql.Dialect{}, old[0], new[0], true, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("err: rename: unexpected SQL returned.\nactual:\n%s\nexpected:\n%s\n", actual, expected)
+	}
+
+	renamed := new[0].WithTableName("build_renamed_test")
+	renamed.PreviousNames = []string{"build_test"}
+	if names := GetRenamedTableNames(old, mysql.Tables{renamed}, false); !reflect.DeepEqual(names, map[string]string{"build_renamed_test": "build_test"}) {
+		t.Fatalf("err: rename: unexpected table names returned.\nactual:\n%v\n", names)
+	}
+	expected = []string{
+		"rename table `build_test` to `build_renamed_test`",
+		"alter table `build_renamed_test` change `name` `nickname` varchar(64) not null ,\n" +
+			"	change `email` `mail` varchar(255) not null ,\n" +
+			"	rename index `name` to `uniq_nickname`\n\t",
+	}
+	actual, err = Build(db, mysql.Dialect{}, old[0], renamed, true, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("err: rename: unexpected SQL returned.\nactual:\n%s\nexpected:\n%s\n", actual, expected)
+	}
+
+	queries, err := Build(db, mysql.Dialect{}, nil, old[0], true, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	queries = append(queries, actual...)
+	queries = append(queries, renamed.ToDropSQL())
+	for _, sql := range queries {
+		if _, err := db.Exec(sql); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestForeignKey(t *testing.T) {
+	old, err := getTables("./_test/table4.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	new, err := getTables("./_test/table5.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{
+		"create table if not exists `build_fk_child` (\n" +
+			"	`id` int(11) unsigned not null auto_increment,\n" +
+			"	`parent_id` int(11) unsigned not null ,\n" +
+			"	primary key (`id`),\n" +
+			"	key `fk_parent` (`parent_id`),\n" +
+			"	constraint `fk_parent` foreign key (`parent_id`) references `build_fk_parent` (`id`) on delete restrict on update restrict\n" +
+			") engine=InnoDB default charset=utf8 ",
+	}
+	actual, err := Build(db, mysql.Dialect{}, nil, old[1], true, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("err: create: unexpected SQL returned.\nactual:\n%s\nexpected:\n%s\n", actual, expected)
+	}
+	for _, table := range old {
+		queries, err := Build(db, mysql.Dialect{}, nil, table, true, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, sql := range queries {
+			if _, err := db.Exec(sql); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	expected = []string{
+		"alter table `build_fk_child` drop foreign key `fk_parent`\n\t",
+		"alter table `build_fk_child` add constraint `fk_parent` foreign key (`parent_id`) references `build_fk_parent` (`id`) on delete cascade on update restrict\n\t",
+	}
+	actual, err = Build(db, mysql.Dialect{}, old[1], new[1], true, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("err: alter: unexpected SQL returned.\nactual:\n%s\nexpected:\n%s\n", actual, expected)
+	}
+	for _, sql := range actual {
+		if _, err := db.Exec(sql); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestGeneratedColumn(t *testing.T) {
+	tables, err := getTables("./_test/table1.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	base := tables[0]
+	withGenerated := func(generationType, expression string) *mysql.Table {
+		table := base.WithTableName(base.TableName)
+		table.Columns = append(table.Columns, &mysql.Column{
+			TableName:            table.TableName,
+			ColumnName:           "name_length",
+			OrdinalPosition:      int32(len(table.Columns) + 1),
+			Nullable:             "YES",
+			DataType:             "int",
+			ColumnType:           "int(11)",
+			Extra:                mysql.JsonNullString{NullString: sql.NullString{String: generationType, Valid: true}},
+			GenerationExpression: expression,
+		})
+		return table
+	}
+	virtual := withGenerated("VIRTUAL GENERATED", "char_length(`name`)")
+	tests := []struct {
+		old      *mysql.Table
+		new      *mysql.Table
+		expected []string
+	}{
+		{
+			base,
+			virtual,
+			[]string{"alter table `build_test` add `name_length` int(11) as (char_length(`name`)) virtual after `deleted_at`\n\t"},
+		},
+		{
+			virtual,
+			withGenerated("VIRTUAL GENERATED", "length(`name`)"),
+			[]string{"alter table `build_test` modify `name_length` int(11) as (length(`name`)) virtual\n\t"},
+		},
+		{
+			virtual,
+			withGenerated("STORED GENERATED", "char_length(`name`)"),
+			[]string{"alter table `build_test` drop `name_length`,\n\tadd `name_length` int(11) as (char_length(`name`)) stored after `deleted_at`\n\t"},
+		},
+		{virtual, withGenerated("VIRTUAL GENERATED", "char_length(`name`)"), nil},
+	}
+	// the index and the CHECK constraint on the recreated column are dropped and added again
+	withDependencies := func(table *mysql.Table) *mysql.Table {
+		table.Indices = append(table.Indices, mysql.Index{{Table: table.TableName, NonUniue: 1, KeyName: "k_name_length", SeqInIndex: 1, ColumnName: "name_length", IndexType: "BTREE"}})
+		table.CheckConstraints = mysql.CheckConstraints{{TableName: table.TableName, ConstraintName: "c_name_length", CheckClause: "(`name_length` > 0)", Enforced: "YES"}}
+		return table
+	}
+	tests = append(tests, struct {
+		old      *mysql.Table
+		new      *mysql.Table
+		expected []string
+	}{
+		withDependencies(withGenerated("VIRTUAL GENERATED", "char_length(`name`)")),
+		withDependencies(withGenerated("STORED GENERATED", "char_length(`name`)")),
+		[]string{
+			"alter table `build_test` drop constraint `c_name_length`\n\t",
+			"alter table `build_test` drop key `k_name_length`,\n" +
+				"\tdrop `name_length`,\n" +
+				"\tadd `name_length` int(11) as (char_length(`name`)) stored after `deleted_at`,\n" +
+				"\tadd key `k_name_length` (`name_length`),\n" +
+				"\tadd constraint `c_name_length` check ((`name_length` > 0))\n\t",
+		},
+	})
+	for _, test := range tests {
+		actual, err := Build(db, mysql.Dialect{}, test.old, test.new, true, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(actual, test.expected) {
+			t.Fatalf("err: generated column: unexpected SQL returned.\nactual:\n%q\nexpected:\n%q\n", actual, test.expected)
+		}
+	}
+}
+
+func TestCheckConstraint(t *testing.T) {
+	tables, err := getTables("./_test/table1.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	base := tables[0]
+	withChecks := func(checks ...*mysql.CheckConstraint) *mysql.Table {
+		table := base.WithTableName(base.TableName)
+		table.CheckConstraints = checks
+		return table
+	}
+	gender := &mysql.CheckConstraint{ConstraintName: "build_test_gender", TableName: "build_test", CheckClause: "(`gender` in (1,2))", Enforced: "YES"}
+	genderChanged := &mysql.CheckConstraint{ConstraintName: "build_test_gender", TableName: "build_test", CheckClause: "(`gender` in (0,1,2))", Enforced: "YES"}
+	genderNotEnforced := &mysql.CheckConstraint{ConstraintName: "build_test_gender", TableName: "build_test", CheckClause: "(`gender` in (1,2))", Enforced: "NO"}
+	genderMariaDB := &mysql.CheckConstraint{ConstraintName: "build_test_gender", TableName: "build_test", CheckClause: "(`gender` in (1,2))"}
+	country := &mysql.CheckConstraint{ConstraintName: "build_test_country", TableName: "build_test", CheckClause: "(`country` > 0)", Enforced: "YES"}
+	tests := []struct {
+		old      *mysql.Table
+		new      *mysql.Table
+		expected []string
+	}{
+		{
+			base,
+			withChecks(gender, country),
+			[]string{"alter table `build_test` add constraint `build_test_country` check ((`country` > 0)),\n\tadd constraint `build_test_gender` check ((`gender` in (1,2)))\n\t"},
+		},
+		{
+			withChecks(gender, country),
+			withChecks(genderChanged),
+			[]string{
+				"alter table `build_test` drop constraint `build_test_country`,\n\tdrop constraint `build_test_gender`\n\t",
+				"alter table `build_test` add constraint `build_test_gender` check ((`gender` in (0,1,2)))\n\t",
+			},
+		},
+		{
+			withChecks(gender),
+			withChecks(genderNotEnforced),
+			[]string{"alter table `build_test` alter check `build_test_gender` not enforced\n\t"},
+		},
+		{withChecks(gender), withChecks(gender), nil},
+		// MariaDB does not have the enforcement
+		{withChecks(genderMariaDB), withChecks(genderNotEnforced), nil},
+	}
+	for _, test := range tests {
+		actual, err := Build(db, mysql.Dialect{}, test.old, test.new, true, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(actual, test.expected) {
+			t.Fatalf("err: check constraint: unexpected SQL returned.\nactual:\n%q\nexpected:\n%q\n", actual, test.expected)
+		}
+	}
+}
+
+func TestIndexVisibilityAndOrder(t *testing.T) {
+	tables, err := getTables("./_test/table1.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	base := tables[0]
+	withK1 := func(f func(info *mysql.IndexColumn)) *mysql.Table {
+		table := base.WithTableName(base.TableName)
+		for _, index := range table.Indices {
+			if index.GetKeyName() != "k1" {
+				continue
+			}
+			for i := range index {
+				f(&index[i])
+			}
+		}
+		return table
+	}
+	visible := withK1(func(info *mysql.IndexColumn) { info.Visible = "YES" })
+	invisible := withK1(func(info *mysql.IndexColumn) { info.Visible = "NO" })
+	desc := withK1(func(info *mysql.IndexColumn) { info.Collation = "D"; info.Visible = "YES" })
+	tests := []struct {
+		old      *mysql.Table
+		new      *mysql.Table
+		expected []string
+	}{
+		{visible, base, nil},
+		{visible, invisible, []string{"alter table `build_test` alter index `k1` invisible\n\t"}},
+		{invisible, base, []string{"alter table `build_test` alter index `k1` visible\n\t"}},
+		{visible, desc, []string{"alter table `build_test` drop key `k1`,\n\tadd key `k1` (`deleted_at` desc)\n\t"}},
+	}
+	for _, test := range tests {
+		actual, err := Build(db, mysql.Dialect{}, test.old, test.new, true, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(actual, test.expected) {
+			t.Fatalf("err: index: unexpected SQL returned.\nactual:\n%q\nexpected:\n%q\n", actual, test.expected)
+		}
+	}
+}
+
+func TestPartition(t *testing.T) {
+	tables, err := getTables("./_test/table1.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	base := tables[0]
+	withPartitions := func(method string, descriptions ...string) *mysql.Table {
+		table := base.WithTableName(base.TableName)
+		table.Partitions = mysql.Partitions{}
+		for i := 0; i+1 < len(descriptions); i += 2 {
+			table.Partitions = append(table.Partitions, &mysql.Partition{
+				TableName:            "build_test",
+				PartitionName:        descriptions[i],
+				PartitionMethod:      method,
+				PartitionExpression:  "`id`",
+				PartitionDescription: mysql.JsonNullString{NullString: sql.NullString{String: descriptions[i+1], Valid: true}},
+			})
+		}
+		return table
+	}
+	ranged := withPartitions(mysql.PartitionMethodRangeExpr, "p0", "10", "p1", "20", "p2", "30")
+	tests := []struct {
+		name     string
+		old      *mysql.Table
+		new      *mysql.Table
+		withDrop bool
+		expected []string
+	}{
+		{
+			"add",
+			ranged,
+			withPartitions(mysql.PartitionMethodRangeExpr, "p0", "10", "p1", "20", "p2", "30", "p3", "40", "p4", "50"),
+			true,
+			[]string{"alter table `build_test` add partition (\n\t\tpartition `p3` values less than (40),\n\t\tpartition `p4` values less than (50)\n\t)\n\t"},
+		},
+		{
+			"add before maxvalue",
+			withPartitions(mysql.PartitionMethodRangeExpr, "p0", "10", "pmax", "MAXVALUE"),
+			withPartitions(mysql.PartitionMethodRangeExpr, "p0", "10", "p1", "20", "pmax", "MAXVALUE"),
+			true,
+			[]string{"alter table `build_test` reorganize partition `pmax` into (\n\t\tpartition `p1` values less than (20),\n\t\tpartition `pmax` values less than MAXVALUE\n\t)\n\t"},
+		},
+		{
+			"drop and add",
+			ranged,
+			withPartitions(mysql.PartitionMethodRangeExpr, "p1", "20", "p2", "30", "p3", "40"),
+			true,
+			[]string{
+				"alter table `build_test` drop partition `p0`\n\t",
+				"alter table `build_test` add partition (\n\t\tpartition `p3` values less than (40)\n\t)\n\t",
+			},
+		},
+		{
+			"add without drop",
+			ranged,
+			withPartitions(mysql.PartitionMethodRangeExpr, "p1", "20", "p2", "30", "p3", "40"),
+			false,
+			[]string{"alter table `build_test` add partition (\n\t\tpartition `p3` values less than (40)\n\t)\n\t"},
+		},
+		{
+			"reorganize",
+			ranged,
+			withPartitions(mysql.PartitionMethodRangeExpr, "p0", "10", "p1a", "15", "p1b", "20", "p2", "30"),
+			true,
+			[]string{"alter table `build_test` reorganize partition `p1` into (\n\t\tpartition `p1a` values less than (15),\n\t\tpartition `p1b` values less than (20)\n\t)\n\t"},
+		},
+		{
+			"add list",
+			withPartitions(mysql.PartitionMethodList, "p0", "1,2", "p2", "5"),
+			withPartitions(mysql.PartitionMethodList, "p0", "1,2", "p1", "3,4", "p2", "5"),
+			true,
+			[]string{"alter table `build_test` add partition (\n\t\tpartition `p1` values in (3,4)\n\t)\n\t"},
+		},
+		{
+			"remove partitioning",
+			ranged,
+			base,
+			true,
+			[]string{"alter table `build_test` remove partitioning\n\t"},
+		},
+		{
+			"partition",
+			base,
+			withPartitions(mysql.PartitionMethodRangeExpr, "p0", "10"),
+			true,
+			[]string{"alter table `build_test` partition by range (`id`) (\n\t\tpartition `p0` values less than (10)\n\t)\n\t"},
+		},
+		{
+			"repartition by another method",
+			ranged,
+			withPartitions(mysql.PartitionMethodList, "p0", "1"),
+			true,
+			[]string{"alter table `build_test` partition by list (`id`) (\n\t\tpartition `p0` values in (1)\n\t)\n\t"},
+		},
+		{
+			"repartition reordered partitions",
+			ranged,
+			withPartitions(mysql.PartitionMethodRangeExpr, "p1", "20", "p0", "10", "p2", "30"),
+			true,
+			[]string{"alter table `build_test` partition by range (`id`) (\n\t\tpartition `p1` values less than (20),\n\t\tpartition `p0` values less than (10),\n\t\tpartition `p2` values less than (30)\n\t)\n\t"},
+		},
+		{"no change", ranged, withPartitions(mysql.PartitionMethodRangeExpr, "p0", "10", "p1", "20", "p2", "30"), true, nil},
+	}
+	for _, test := range tests {
+		actual, err := Build(db, mysql.Dialect{}, test.old, test.new, test.withDrop, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(actual, test.expected) {
+			t.Fatalf("err: %s: unexpected SQL returned.\nactual:\n%q\nexpected:\n%q\n", test.name, actual, test.expected)
+		}
+	}
+}
+
+func TestView(t *testing.T) {
+	old := &mysql.View{TableName: "build_view", ViewDefinition: "select 1 AS `id`", CheckOption: "NONE", SecurityType: "DEFINER", Algorithm: "UNDEFINED"}
+	new := &mysql.View{TableName: "build_view", ViewDefinition: "select 2 AS `id`", CheckOption: "NONE", SecurityType: "INVOKER", Algorithm: "MERGE"}
+	tests := []struct {
+		old      *mysql.View
+		new      *mysql.View
+		withDrop bool
+		expected []string
+	}{
+		{nil, old, true, []string{"create or replace algorithm=undefined sql security definer view `build_view` as select 1 AS `id`"}},
+		{old, old, true, nil},
+		{old, new, true, []string{"create or replace algorithm=merge sql security invoker view `build_view` as select 2 AS `id`"}},
+		{new, nil, false, nil},
+		{new, nil, true, []string{"drop view if exists `build_view`"}},
+	}
+	for _, test := range tests {
+		actual, err := BuildView(mysql.Dialect{}, test.old, test.new, test.withDrop)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(actual, test.expected) {
+			t.Fatalf("err: view: unexpected SQL returned.\nactual:\n%s\nexpected:\n%s\n", actual, test.expected)
+		}
+		for _, sql := range actual {
+			if _, err := db.Exec(sql); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+}
+
+func TestTriggerAndRoutine(t *testing.T) {
+	new, err := getTables("./_test/table1.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	queries, err := Build(db, mysql.Dialect{}, nil, new[0], true, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, sql := range queries {
+		if _, err := db.Exec(sql); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	oldTrigger := &mysql.Trigger{TriggerName: "build_trigger", EventManipulation: "INSERT", EventObjectTable: "build_test", ActionTiming: "BEFORE", ActionStatement: "set new.`country` = 1"}
+	newTrigger := &mysql.Trigger{TriggerName: "build_trigger", EventManipulation: "INSERT", EventObjectTable: "build_test", ActionTiming: "BEFORE", ActionStatement: "BEGIN\n\tset new.`country` = 2;\nEND"}
+	oldRoutine := &mysql.Routine{RoutineName: "build_procedure", RoutineType: mysql.RoutineTypeProcedure, Definition: "CREATE PROCEDURE `build_procedure`()\nselect 1"}
+	newRoutine := &mysql.Routine{RoutineName: "build_procedure", RoutineType: mysql.RoutineTypeProcedure, Definition: "CREATE PROCEDURE `build_procedure`()\nBEGIN\n\tselect 2;\nEND"}
+	tests := []struct {
+		build    func() ([]string, error)
+		expected []string
+	}{
+		{
+			func() ([]string, error) { return BuildTrigger(mysql.Dialect{}, nil, oldTrigger, true) },
+			[]string{"create trigger `build_trigger` before insert on `build_test` for each row set new.`country` = 1"},
+		},
+		{
+			func() ([]string, error) { return BuildTrigger(mysql.Dialect{}, oldTrigger, oldTrigger, true) },
+			nil,
+		},
+		{
+			func() ([]string, error) { return BuildTrigger(mysql.Dialect{}, oldTrigger, newTrigger, false) },
+			[]string{"drop trigger if exists `build_trigger`", "create trigger `build_trigger` before insert on `build_test` for each row BEGIN\n\tset new.`country` = 2;\nEND"},
+		},
+		{
+			func() ([]string, error) { return BuildTrigger(mysql.Dialect{}, newTrigger, nil, true) },
+			[]string{"drop trigger if exists `build_trigger`"},
+		},
+		{
+			func() ([]string, error) { return BuildRoutine(mysql.Dialect{}, nil, oldRoutine, true) },
+			[]string{"CREATE PROCEDURE `build_procedure`()\nselect 1"},
+		},
+		{
+			func() ([]string, error) { return BuildRoutine(mysql.Dialect{}, oldRoutine, newRoutine, false) },
+			[]string{"drop procedure if exists `build_procedure`", "CREATE PROCEDURE `build_procedure`()\nBEGIN\n\tselect 2;\nEND"},
+		},
+		{
+			func() ([]string, error) { return BuildRoutine(mysql.Dialect{}, newRoutine, nil, false) },
+			nil,
+		},
+		{
+			func() ([]string, error) { return BuildRoutine(mysql.Dialect{}, newRoutine, nil, true) },
+			[]string{"drop procedure if exists `build_procedure`"},
+		},
+	}
+	for _, test := range tests {
+		actual, err := test.build()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(actual, test.expected) {
+			t.Fatalf("err: routine: unexpected SQL returned.\nactual:\n%s\nexpected:\n%s\n", actual, test.expected)
+		}
+		for _, sql := range actual {
+			if _, err := db.Exec(sql); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+}
+
 func getTables(filename string) (mysql.Tables, error) {
 	buf, err := ioutil.ReadFile(filename)
 	if err != nil {
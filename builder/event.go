@@ -0,0 +1,29 @@
+package builder
+
+import (
+	"fmt"
+
+	"github.com/dev-cloverlab/carpenter/dialect"
+	"github.com/dev-cloverlab/carpenter/dialect/mysql"
+)
+
+// BuildEvent returns the query to create the event, to alter the clauses of the event which differ,
+// or to drop the event which is not in the new definition when withDrop is set.
+func BuildEvent(d dialect.EventDialect, old, new *mysql.Event, withDrop bool) (queries []string, err error) {
+	if old == nil && new == nil {
+		return queries, fmt.Errorf("err: Both pointer of the specified new and old is nil.")
+	}
+	if new == nil {
+		if withDrop {
+			queries = append(queries, d.ToDropEventSQL(old))
+		}
+		return queries, nil
+	}
+	if old == nil {
+		return append(queries, d.ToCreateEventSQL(new)), nil
+	}
+	if q := d.ToAlterEventSQL(old, new); len(q) > 0 {
+		queries = append(queries, q)
+	}
+	return queries, nil
+}
@@ -0,0 +1,207 @@
+package builder
+
+import (
+	"fmt"
+
+	"github.com/dev-cloverlab/carpenter/dialect"
+	"github.com/dev-cloverlab/carpenter/dialect/mysql"
+)
+
+// GetRenamedTableNames pairs the tables which exist only in new with the tables which exist only in old.
+// A pair is made when the new table has the old table name in PreviousNames, or when detectRename is
+// turned on and both tables have the same definition. It returns a map of new table name to old table name.
+func GetRenamedTableNames(old, new mysql.Tables, detectRename bool) map[string]string {
+	oldMap := old.GroupByTableName()
+	newMap := new.GroupByTableName()
+	dropped := map[string]*mysql.Table{}
+	for _, tableName := range old.GetSortedTableNames() {
+		if _, ok := newMap[tableName]; !ok {
+			dropped[tableName] = oldMap[tableName]
+		}
+	}
+	renamed := map[string]string{}
+	for _, tableName := range new.GetSortedTableNames() {
+		if _, ok := oldMap[tableName]; ok {
+			continue
+		}
+		table := newMap[tableName]
+		for _, name := range table.PreviousNames {
+			if _, ok := dropped[name]; ok {
+				renamed[tableName] = name
+				delete(dropped, name)
+				break
+			}
+		}
+	}
+	if !detectRename {
+		return renamed
+	}
+	for _, tableName := range new.GetSortedTableNames() {
+		if _, ok := oldMap[tableName]; ok {
+			continue
+		}
+		if _, ok := renamed[tableName]; ok {
+			continue
+		}
+		table := newMap[tableName]
+		for _, name := range old.GetSortedTableNames() {
+			d, ok := dropped[name]
+			if !ok {
+				continue
+			}
+			if d.WithTableName(tableName).ToCreateSQL() == table.ToCreateSQL() {
+				renamed[tableName] = name
+				delete(dropped, name)
+				break
+			}
+		}
+	}
+	return renamed
+}
+
+func willRenameTable(d dialect.Dialect, old, new *mysql.Table) string {
+	if old.TableName == new.TableName {
+		return ""
+	}
+	return d.ToRenameSQL(old, new.TableName)
+}
+
+// getRenamedColumns returns a map of new column name to old column.
+func getRenamedColumns(old, new *mysql.Table, detectRename bool) map[string]*mysql.Column {
+	newCols := new.Columns.GroupByColumnName()
+	oldCols := old.Columns.GroupByColumnName()
+	dropped := map[string]*mysql.Column{}
+	for _, column := range old.Columns {
+		if _, ok := newCols[column.ColumnName]; !ok {
+			dropped[column.ColumnName] = column
+		}
+	}
+	added := mysql.Columns{}
+	for _, column := range new.Columns {
+		if _, ok := oldCols[column.ColumnName]; !ok {
+			added = append(added, column)
+		}
+	}
+
+	renamed := map[string]*mysql.Column{}
+	for _, column := range added {
+		for _, name := range column.PreviousNames {
+			if d, ok := dropped[name]; ok {
+				renamed[column.ColumnName] = d
+				delete(dropped, name)
+				break
+			}
+		}
+	}
+	if !detectRename {
+		return renamed
+	}
+	for _, column := range added {
+		if _, ok := renamed[column.ColumnName]; ok {
+			continue
+		}
+		for _, d := range old.Columns {
+			if _, ok := dropped[d.ColumnName]; !ok {
+				continue
+			}
+			if d.OrdinalPosition == column.OrdinalPosition && d.EqualDefinition(column) {
+				renamed[column.ColumnName] = d
+				delete(dropped, d.ColumnName)
+				break
+			}
+		}
+	}
+	return renamed
+}
+
+// getRenamedIndices returns a map of new key name to old index.
+func getRenamedIndices(old, new *mysql.Table, detectRename bool) map[string]mysql.Index {
+	newIndicesMap := new.Indices.GroupByKeyName()
+	oldIndicesMap := old.Indices.GroupByKeyName()
+	dropped := map[string]mysql.Index{}
+	for _, keyName := range old.Indices.GetSortedKeys() {
+		if _, ok := newIndicesMap[keyName]; !ok {
+			dropped[keyName] = oldIndicesMap[keyName][0]
+		}
+	}
+
+	renamed := map[string]mysql.Index{}
+	pair := func(keyName string, index mysql.Index, candidates []string) {
+		for _, name := range candidates {
+			d, ok := dropped[name]
+			if !ok || !d.EqualDefinition(index) {
+				continue
+			}
+			renamed[keyName] = d
+			delete(dropped, name)
+			return
+		}
+	}
+	for _, keyName := range new.Indices.GetSortedKeys() {
+		if _, ok := oldIndicesMap[keyName]; ok {
+			continue
+		}
+		index := newIndicesMap[keyName][0]
+		pair(keyName, index, index[0].PreviousKeyNames)
+	}
+	if !detectRename {
+		return renamed
+	}
+	for _, keyName := range new.Indices.GetSortedKeys() {
+		if _, ok := oldIndicesMap[keyName]; ok {
+			continue
+		}
+		if _, ok := renamed[keyName]; ok {
+			continue
+		}
+		pair(keyName, newIndicesMap[keyName][0], old.Indices.GetSortedKeys())
+	}
+	return renamed
+}
+
+func willChangeColumn(new *mysql.Table, renamedColumns map[string]*mysql.Column) []*mysql.ChangeColumn {
+	newCols := new.Columns.GroupByColumnName()
+	changes := []*mysql.ChangeColumn{}
+	for _, colName := range new.Columns.GetSortedColumnNames() {
+		if oldCol, ok := renamedColumns[colName]; ok {
+			changes = append(changes, &mysql.ChangeColumn{OldName: oldCol.ColumnName, Column: newCols[colName]})
+		}
+	}
+	return changes
+}
+
+func willRenameIndex(new *mysql.Table, renamedIndices map[string]mysql.Index) []*mysql.RenameIndex {
+	renames := []*mysql.RenameIndex{}
+	for _, keyName := range new.Indices.GetSortedKeys() {
+		if oldIndex, ok := renamedIndices[keyName]; ok {
+			renames = append(renames, &mysql.RenameIndex{Index: oldIndex, Name: keyName})
+		}
+	}
+	return renames
+}
+
+func getRenamedColumnNames(renamedColumns map[string]*mysql.Column) map[string]string {
+	names := make(map[string]string, len(renamedColumns))
+	for newName, oldCol := range renamedColumns {
+		names[oldCol.ColumnName] = newName
+	}
+	return names
+}
+
+func getRenamedKeyNames(renamedIndices map[string]mysql.Index) map[string]string {
+	names := make(map[string]string, len(renamedIndices))
+	for newName, oldIndex := range renamedIndices {
+		names[oldIndex.GetKeyName()] = newName
+	}
+	return names
+}
+
+func validateTableName(old, new *mysql.Table, detectRename bool) error {
+	if old == nil || new == nil || old.TableName == new.TableName {
+		return nil
+	}
+	if detectRename || new.HasPreviousName(old.TableName) {
+		return nil
+	}
+	return fmt.Errorf("err: Table name of the specified new and old is a difference")
+}
@@ -0,0 +1,52 @@
+package builder
+
+import (
+	"fmt"
+
+	"github.com/dev-cloverlab/carpenter/dialect"
+	"github.com/dev-cloverlab/carpenter/dialect/mysql"
+)
+
+// BuildTrigger returns the queries to drop and create the trigger when the definition differs,
+// or the query to drop the trigger which is not in the new definition when withDrop is set.
+func BuildTrigger(d dialect.RoutineDialect, old, new *mysql.Trigger, withDrop bool) (queries []string, err error) {
+	if old == nil && new == nil {
+		return queries, fmt.Errorf("err: Both pointer of the specified new and old is nil.")
+	}
+	if new == nil {
+		if withDrop {
+			queries = append(queries, d.ToDropTriggerSQL(old))
+		}
+		return queries, nil
+	}
+	if old != nil && old.Equal(new) {
+		return queries, nil
+	}
+	// triggers can not be altered
+	if old != nil {
+		queries = append(queries, d.ToDropTriggerSQL(old))
+	}
+	return append(queries, d.ToCreateTriggerSQL(new)), nil
+}
+
+// BuildRoutine returns the queries to drop and create the stored routine when the definition differs,
+// or the query to drop the routine which is not in the new definition when withDrop is set.
+func BuildRoutine(d dialect.RoutineDialect, old, new *mysql.Routine, withDrop bool) (queries []string, err error) {
+	if old == nil && new == nil {
+		return queries, fmt.Errorf("err: Both pointer of the specified new and old is nil.")
+	}
+	if new == nil {
+		if withDrop {
+			queries = append(queries, d.ToDropRoutineSQL(old))
+		}
+		return queries, nil
+	}
+	if old != nil && old.Equal(new) {
+		return queries, nil
+	}
+	// alter procedure and alter function can not change the body
+	if old != nil {
+		queries = append(queries, d.ToDropRoutineSQL(old))
+	}
+	return append(queries, d.ToCreateRoutineSQL(new)), nil
+}
@@ -0,0 +1,26 @@
+package builder
+
+import (
+	"fmt"
+
+	"github.com/dev-cloverlab/carpenter/dialect"
+	"github.com/dev-cloverlab/carpenter/dialect/mysql"
+)
+
+// BuildView returns the query to create or replace the view when the definition differs,
+// or the query to drop the view which is not in the new definition when withDrop is set.
+func BuildView(d dialect.ViewDialect, old, new *mysql.View, withDrop bool) (queries []string, err error) {
+	if old == nil && new == nil {
+		return queries, fmt.Errorf("err: Both pointer of the specified new and old is nil.")
+	}
+	if new == nil {
+		if withDrop {
+			queries = append(queries, d.ToDropViewSQL(old))
+		}
+		return queries, nil
+	}
+	if old == nil || !old.Equal(new) {
+		queries = append(queries, d.ToCreateViewSQL(new))
+	}
+	return queries, nil
+}
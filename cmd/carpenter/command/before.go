@@ -7,10 +7,14 @@ import (
 	"fmt"
 
 	"github.com/codegangsta/cli"
+	"github.com/dev-cloverlab/carpenter/dialect"
 	_ "github.com/go-sql-driver/mysql"
+	_ "github.com/lib/pq"
+	_ "github.com/mattn/go-sqlite3"
 )
 
 var db *sql.DB
+var sqlDialect dialect.Dialect
 var schema string
 var verbose bool
 var dryrun bool
@@ -32,12 +36,20 @@ func Before(c *cli.Context) error {
 		return fmt.Errorf("err: Specify required `--data-soruce' option")
 	}
 	var err error
-	db, err = sql.Open("mysql", fmt.Sprintf("%s/%s?charset=utf8", datasource, schema))
+	sqlDialect, err = dialect.Get(c.GlobalString("driver"))
+	if err != nil {
+		return err
+	}
+	db, err = sql.Open(sqlDialect.DriverName(), sqlDialect.DataSourceName(datasource, schema))
 	if err != nil {
 		return fmt.Errorf("err: db.Open is failed for reason %v", err)
 	}
 	db.SetMaxIdleConns(maxIdleConns)
 	db.SetMaxOpenConns(maxOpenConns)
+	// the pragma statements only affect the connection and SQLite allows only one writer
+	if sqlDialect.DriverName() == "sqlite3" {
+		db.SetMaxOpenConns(1)
+	}
 	db.SetConnMaxLifetime(time.Minute)
 	return nil
 }
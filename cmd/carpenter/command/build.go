@@ -4,54 +4,105 @@ import (
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
+	"sort"
 	"strings"
 	"sync"
 
 	"github.com/codegangsta/cli"
 	"github.com/dev-cloverlab/carpenter/builder"
+	"github.com/dev-cloverlab/carpenter/dialect"
 	"github.com/dev-cloverlab/carpenter/dialect/mysql"
+	"github.com/dev-cloverlab/carpenter/planner"
 )
 
 func CmdBuild(c *cli.Context) {
 	// Write your code here
 	dirPath := c.String("dir")
 	withDrop := c.Bool("with-drop")
-	queries, errs := makeBuildQueries(dirPath, withDrop)
-	if len(errs) > 0 {
-		panic(fmt.Errorf("err: makeQueries failed for reason\n%s", strings.Join(getErrorMessages(errs), "\n")))
+	detectRename := c.Bool("detect-rename")
+	journalPath := c.String("journal")
+	if len(journalPath) <= 0 {
+		journalPath = fmt.Sprintf(defaultJournalFmt, schema)
 	}
-	if err := execute(queries); err != nil {
+	target, err := newJournalTarget(sqlDialect.DriverName(), schema, c.GlobalString("data-source"), dirPath)
+	if err != nil {
+		panic(err)
+	}
+	j, err := loadJournal(journalPath, target)
+	if err != nil {
+		panic(err)
+	}
+	if j != nil {
+		// the queries of the failed build are resumed instead of the difference of the half-migrated tables
+		fmt.Printf("resume build from query %d of %d (journal %s)\n", j.Applied+1, len(j.Queries), journalPath)
+		for _, query := range j.appliedQueries() {
+			fmt.Println("applied: " + query + ";")
+		}
+	} else {
+		changes, errs := makeBuildChanges(dirPath, withDrop, detectRename)
+		if len(errs) > 0 {
+			panic(fmt.Errorf("err: makeQueries failed for reason\n%s", strings.Join(getErrorMessages(errs), "\n")))
+		}
+		j = &journal{Target: target, Queries: (&planner.Plan{Changes: changes}).Queries()}
+	}
+	if err := executeWithJournal(journalPath, j); err != nil {
 		panic(fmt.Errorf("err: execute failed for reason %s", err))
 	}
 }
 
-func makeBuildQueries(path string, withDrop bool) (queries []string, errs []error) {
+// makeBuildChanges returns the changes of the tables and the other objects in the order to be executed.
+// Views, triggers, routines and events which are removed are dropped before the tables,
+// and the others are created after the tables in the order of routines, views, triggers and events
+// because they depend on the tables and may call routines.
+func makeBuildChanges(path string, withDrop, detectRename bool) (changes []*planner.Change, errs []error) {
 	files, err := walk(path, ".json")
 	if err != nil {
 		return nil, []error{err}
 	}
-	new := mysql.Tables{}
+	s := &mysql.Schema{}
 	for _, file := range files {
-		tables, err := parseJSON(file[0])
+		objects, err := parseJSON(file[0])
 		if err != nil {
 			return nil, []error{err}
 		}
-		new = append(new, tables...)
+		s.Merge(objects)
+	}
+	views, err := makeViewChanges(s.Views, withDrop)
+	if err != nil {
+		return nil, []error{err}
+	}
+	triggers, routines, err := makeRoutineChanges(s.Triggers, s.Routines, withDrop)
+	if err != nil {
+		return nil, []error{err}
+	}
+	events, err := makeEventChanges(s.Events, withDrop)
+	if err != nil {
+		return nil, []error{err}
 	}
-	old, err := mysql.GetTables(db, schema)
+	changes = append(changes, events.drops...)
+	changes = append(changes, views.drops...)
+	changes = append(changes, triggers.drops...)
+	changes = append(changes, routines.drops...)
+	new := s.Tables
+	old, err := sqlDialect.GetTables(db, schema)
 	if err != nil {
 		return nil, []error{err}
 	}
+	type result struct {
+		tableName string
+		queries   []string
+	}
 	errCh := make(chan error)
-	sqlCh := make(chan []string)
+	sqlCh := make(chan result)
 	doneCh := make(chan bool)
+	results := map[string][]string{}
 	go func() {
 		for {
 			select {
 			case err := <-errCh:
 				errs = append(errs, err)
-			case query := <-sqlCh:
-				queries = append(queries, query...)
+			case r := <-sqlCh:
+				results[r.tableName] = r.queries
 			case <-doneCh:
 				return
 			}
@@ -60,7 +111,14 @@ func makeBuildQueries(path string, withDrop bool) (queries []string, errs []erro
 
 	newMap := new.GroupByTableName()
 	oldMap := old.GroupByTableName()
+	// renamed tables are compared with the new table name
+	for newName, oldName := range builder.GetRenamedTableNames(old, new, detectRename) {
+		oldMap[newName] = oldMap[oldName]
+		delete(oldMap, oldName)
+	}
 	tableNames := getTableNames(newMap, oldMap)
+	// the foreign keys which make a cycle are added after all tables are created
+	deferred := getDeferredForeignKeys(tableNames, newMap, oldMap)
 	wg := &sync.WaitGroup{}
 	for _, tableName := range tableNames {
 		oTbl, ok := oldMap[tableName]
@@ -70,35 +128,216 @@ func makeBuildQueries(path string, withDrop bool) (queries []string, errs []erro
 		nTbl, ok := newMap[tableName]
 		if !ok {
 			nTbl = nil
+		} else if fks, ok := deferred[tableName]; ok {
+			nTbl = withoutForeignKeys(nTbl, fks)
 		}
 		wg.Add(1)
-		go func(o, n *mysql.Table) {
+		go func(t string, o, n *mysql.Table) {
 			defer wg.Done()
-			queries, err := builder.Build(db, o, n, withDrop)
+			queries, err := builder.Build(db, sqlDialect, o, n, withDrop, detectRename)
 			if err != nil {
 				errCh <- err
 				return
 			}
-			sqlCh <- queries
-		}(oTbl, nTbl)
+			sqlCh <- result{tableName: t, queries: queries}
+		}(tableName, oTbl, nTbl)
 	}
 	wg.Wait()
 
 	doneCh <- true
 
-	return queries, errs
+	// referenced tables have to be created before and dropped after the referring tables
+	sorted := sortTableNamesByDependency(tableNames, newMap, oldMap)
+	for _, tableName := range sorted {
+		if _, ok := newMap[tableName]; ok && len(results[tableName]) > 0 {
+			kind := planner.KindAlter
+			if _, ok := oldMap[tableName]; !ok {
+				kind = planner.KindCreate
+			}
+			changes = append(changes, &planner.Change{TableName: tableName, Kind: kind, Queries: results[tableName]})
+		}
+	}
+	for _, tableName := range sorted {
+		fks, ok := deferred[tableName]
+		if !ok {
+			continue
+		}
+		queries, err := builder.Build(db, sqlDialect, withoutForeignKeys(newMap[tableName], fks), newMap[tableName], withDrop, false)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if len(queries) > 0 {
+			changes = append(changes, &planner.Change{TableName: tableName, Kind: planner.KindAlter, Queries: queries})
+		}
+	}
+	for i := len(sorted) - 1; i >= 0; i-- {
+		if _, ok := newMap[sorted[i]]; !ok && len(results[sorted[i]]) > 0 {
+			changes = append(changes, &planner.Change{TableName: sorted[i], Kind: planner.KindDrop, Queries: results[sorted[i]]})
+		}
+	}
+	changes = append(changes, routines.creates...)
+	changes = append(changes, views.creates...)
+	changes = append(changes, triggers.creates...)
+	changes = append(changes, events.creates...)
+
+	return changes, errs
 }
 
-func parseJSON(filename string) (mysql.Tables, error) {
-	buf, err := ioutil.ReadFile(filename)
+// objectChanges is the changes of the objects other than tables.
+// The changes to drop the removed objects are executed before the tables and the others are executed after them.
+type objectChanges struct {
+	creates []*planner.Change
+	drops   []*planner.Change
+}
+
+// add adds the queries of the object in the order of creation.
+// The objects which are removed are dropped in the reverse order.
+func (m *objectChanges) add(name string, inNew, inOld bool, queries []string) {
+	if len(queries) <= 0 {
+		return
+	}
+	if !inNew {
+		m.drops = append([]*planner.Change{{TableName: name, Kind: planner.KindDrop, Queries: queries}}, m.drops...)
+		return
+	}
+	kind := planner.KindAlter
+	if !inOld {
+		kind = planner.KindCreate
+	}
+	m.creates = append(m.creates, &planner.Change{TableName: name, Kind: kind, Queries: queries})
+}
+
+// makeViewChanges returns the changes of the views.
+// Views are created after and dropped before the views they refer to.
+func makeViewChanges(new mysql.Views, withDrop bool) (*objectChanges, error) {
+	changes := &objectChanges{}
+	vd, ok := sqlDialect.(dialect.ViewDialect)
+	if !ok {
+		if len(new) > 0 {
+			return nil, fmt.Errorf("err: Views are not supported by driver `%s'", sqlDialect.DriverName())
+		}
+		return changes, nil
+	}
+	old, err := vd.GetViews(db, schema)
+	if err != nil {
+		return nil, err
+	}
+	newMap := new.GroupByTableName()
+	oldMap := old.GroupByTableName()
+	for _, viewName := range sortViewNamesByDependency(newMap, oldMap) {
+		queries, err := builder.BuildView(vd, oldMap[viewName], newMap[viewName], withDrop)
+		if err != nil {
+			return nil, err
+		}
+		_, inNew := newMap[viewName]
+		_, inOld := oldMap[viewName]
+		changes.add(viewName, inNew, inOld, queries)
+	}
+	return changes, nil
+}
+
+// makeRoutineChanges returns the changes of the triggers and the stored routines sorted by the names.
+func makeRoutineChanges(newTriggers mysql.Triggers, newRoutines mysql.Routines, withDrop bool) (triggers, routines *objectChanges, err error) {
+	triggers, routines = &objectChanges{}, &objectChanges{}
+	rd, ok := sqlDialect.(dialect.RoutineDialect)
+	if !ok {
+		if len(newTriggers) > 0 || len(newRoutines) > 0 {
+			return nil, nil, fmt.Errorf("err: Triggers and routines are not supported by driver `%s'", sqlDialect.DriverName())
+		}
+		return triggers, routines, nil
+	}
+
+	oldTriggers, err := rd.GetTriggers(db, schema)
+	if err != nil {
+		return nil, nil, err
+	}
+	newTriggerMap := newTriggers.GroupByTriggerName()
+	oldTriggerMap := oldTriggers.GroupByTriggerName()
+	for _, name := range getSortedNames(newTriggers.GetSortedTriggerNames(), oldTriggers.GetSortedTriggerNames()) {
+		queries, err := builder.BuildTrigger(rd, oldTriggerMap[name], newTriggerMap[name], withDrop)
+		if err != nil {
+			return nil, nil, err
+		}
+		_, inNew := newTriggerMap[name]
+		_, inOld := oldTriggerMap[name]
+		triggers.add(name, inNew, inOld, queries)
+	}
+
+	oldRoutines, err := rd.GetRoutines(db, schema)
+	if err != nil {
+		return nil, nil, err
+	}
+	newRoutineMap := newRoutines.GroupByKey()
+	oldRoutineMap := oldRoutines.GroupByKey()
+	for _, key := range getSortedNames(newRoutines.GetSortedKeys(), oldRoutines.GetSortedKeys()) {
+		queries, err := builder.BuildRoutine(rd, oldRoutineMap[key], newRoutineMap[key], withDrop)
+		if err != nil {
+			return nil, nil, err
+		}
+		routine, inNew := newRoutineMap[key]
+		_, inOld := oldRoutineMap[key]
+		if !inNew {
+			routine = oldRoutineMap[key]
+		}
+		routines.add(routine.RoutineName, inNew, inOld, queries)
+	}
+	return triggers, routines, nil
+}
+
+// makeEventChanges returns the changes of the scheduled events sorted by the names.
+func makeEventChanges(new mysql.Events, withDrop bool) (*objectChanges, error) {
+	changes := &objectChanges{}
+	ed, ok := sqlDialect.(dialect.EventDialect)
+	if !ok {
+		if len(new) > 0 {
+			return nil, fmt.Errorf("err: Events are not supported by driver `%s'", sqlDialect.DriverName())
+		}
+		return changes, nil
+	}
+	old, err := ed.GetEvents(db, schema)
 	if err != nil {
 		return nil, err
 	}
-	tables := mysql.Tables{}
-	if err := json.Unmarshal(buf, &tables); err != nil {
+	newMap := new.GroupByEventName()
+	oldMap := old.GroupByEventName()
+	for _, name := range getSortedNames(new.GetSortedEventNames(), old.GetSortedEventNames()) {
+		queries, err := builder.BuildEvent(ed, oldMap[name], newMap[name], withDrop)
+		if err != nil {
+			return nil, err
+		}
+		_, inNew := newMap[name]
+		_, inOld := oldMap[name]
+		changes.add(name, inNew, inOld, queries)
+	}
+	return changes, nil
+}
+
+// getSortedNames returns the sorted names in either of new or old without duplicates.
+func getSortedNames(new, old []string) []string {
+	nameMap := map[string]struct{}{}
+	names := []string{}
+	for _, name := range append(append([]string{}, new...), old...) {
+		if _, ok := nameMap[name]; ok {
+			continue
+		}
+		nameMap[name] = struct{}{}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func parseJSON(filename string) (*mysql.Schema, error) {
+	buf, err := ioutil.ReadFile(filename)
+	if err != nil {
 		return nil, err
 	}
-	return tables, nil
+	s := &mysql.Schema{}
+	if err := json.Unmarshal(buf, s); err != nil {
+		return nil, fmt.Errorf("err: json.Unmarshal %s failed for reason %s", filename, err)
+	}
+	return s, nil
 }
 
 func getTableNames(new, old map[string]*mysql.Table) []string {
@@ -115,3 +354,160 @@ func getTableNames(new, old map[string]*mysql.Table) []string {
 	}
 	return ret
 }
+
+// getDeferredForeignKeys returns the foreign keys which make a cycle of references between tables like A to B to A,
+// so that the tables are created without them and they are added after all tables are created.
+// The foreign keys which already exist are not deferred. They are compared by the columns instead of the constraint name,
+// because some databases like SQLite do not keep the name.
+func getDeferredForeignKeys(tableNames []string, new, old map[string]*mysql.Table) map[string]mysql.ForeignKeys {
+	_, cycles := visitNamesByDependency(tableNames, getTableDependencies(new, old))
+	deferred := map[string]mysql.ForeignKeys{}
+	for tableName, refs := range cycles {
+		table, ok := new[tableName]
+		if !ok {
+			continue
+		}
+		existing := map[string]struct{}{}
+		if o, ok := old[tableName]; ok {
+			for _, fk := range o.ForeignKeys {
+				existing[getForeignKeyColumnsKey(fk)] = struct{}{}
+			}
+		}
+		cyclic := map[string]struct{}{}
+		for _, ref := range refs {
+			cyclic[ref] = struct{}{}
+		}
+		for _, fk := range table.ForeignKeys {
+			if fk.IsSelfReferenced() || fk.IsExternalReferenced() {
+				continue
+			}
+			if _, ok := cyclic[fk.ReferencedTableName]; !ok {
+				continue
+			}
+			if _, ok := existing[getForeignKeyColumnsKey(fk)]; ok {
+				continue
+			}
+			deferred[tableName] = append(deferred[tableName], fk)
+		}
+	}
+	return deferred
+}
+
+func getForeignKeyColumnsKey(fk *mysql.ForeignKey) string {
+	return fmt.Sprintf("%s>%s.%s", strings.Join(fk.ColumnNames, ","), fk.ReferencedTableName, strings.Join(fk.ReferencedColumnNames, ","))
+}
+
+// withoutForeignKeys returns a copy of the table which does not have the specified foreign keys.
+func withoutForeignKeys(table *mysql.Table, fks mysql.ForeignKeys) *mysql.Table {
+	names := map[string]struct{}{}
+	for _, fk := range fks {
+		names[fk.ConstraintName] = struct{}{}
+	}
+	t := table.WithTableName(table.TableName)
+	t.ForeignKeys = mysql.ForeignKeys{}
+	for _, fk := range table.ForeignKeys {
+		if _, ok := names[fk.ConstraintName]; !ok {
+			t.ForeignKeys = append(t.ForeignKeys, fk)
+		}
+	}
+	return t
+}
+
+func getTableDependencies(new, old map[string]*mysql.Table) map[string][]string {
+	deps := map[string][]string{}
+	for _, tables := range []map[string]*mysql.Table{new, old} {
+		for tableName, table := range tables {
+			deps[tableName] = append(deps[tableName], table.ForeignKeys.GetReferencedTableNames()...)
+		}
+	}
+	return deps
+}
+
+// sortTableNamesByDependency sorts the table names so that each table follows the tables
+// referred by its foreign keys in either of new or old definition.
+// Tables that have no dependency on each other are sorted by name.
+func sortTableNamesByDependency(tableNames []string, new, old map[string]*mysql.Table) []string {
+	return sortNamesByDependency(tableNames, getTableDependencies(new, old))
+}
+
+// sortViewNamesByDependency sorts the names of the views in either of new or old
+// so that each view follows the views referred by its definition.
+func sortViewNamesByDependency(new, old map[string]*mysql.View) []string {
+	nameMap := map[string]struct{}{}
+	for _, views := range []map[string]*mysql.View{new, old} {
+		for viewName := range views {
+			nameMap[viewName] = struct{}{}
+		}
+	}
+	names := make([]string, 0, len(nameMap))
+	for name := range nameMap {
+		names = append(names, name)
+	}
+	deps := map[string][]string{}
+	for _, views := range []map[string]*mysql.View{new, old} {
+		for viewName, view := range views {
+			for _, name := range names {
+				if view.DependsOn(name) {
+					deps[viewName] = append(deps[viewName], name)
+				}
+			}
+		}
+	}
+	return sortNamesByDependency(names, deps)
+}
+
+// sortNamesByDependency sorts the names so that each name follows the names it depends on.
+// Names that have no dependency on each other are sorted by name, and the names not in names are excluded.
+func sortNamesByDependency(names []string, deps map[string][]string) []string {
+	sorted, _ := visitNamesByDependency(names, deps)
+	return sorted
+}
+
+// visitNamesByDependency returns the names sorted by sortNamesByDependency
+// and the dependencies which make a cycle, which are the names each name depends on but are sorted after it.
+func visitNamesByDependency(names []string, deps map[string][]string) ([]string, map[string][]string) {
+	names = append([]string{}, names...)
+	sort.Strings(names)
+
+	sorted := make([]string, 0, len(names))
+	cycles := map[string][]string{}
+	const (
+		visiting = iota + 1
+		visited
+	)
+	states := map[string]int{}
+	var visit func(name string)
+	visit = func(name string) {
+		if states[name] != 0 {
+			// already sorted or circular reference
+			return
+		}
+		states[name] = visiting
+		refs := append([]string{}, deps[name]...)
+		sort.Strings(refs)
+		for _, ref := range refs {
+			if ref != name && states[ref] == visiting {
+				cycles[name] = append(cycles[name], ref)
+			}
+			visit(ref)
+		}
+		states[name] = visited
+		sorted = append(sorted, name)
+	}
+	for _, name := range names {
+		visit(name)
+	}
+
+	// exclude referenced names which are not managed
+	known := make(map[string]struct{}, len(names))
+	for _, name := range names {
+		known[name] = struct{}{}
+	}
+	ret := make([]string, 0, len(names))
+	for _, name := range sorted {
+		if _, ok := known[name]; ok {
+			ret = append(ret, name)
+		}
+	}
+	return ret, cycles
+}
@@ -1,7 +1,241 @@
 package command
 
-import "testing"
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/dev-cloverlab/carpenter/dialect/mysql"
+	"github.com/dev-cloverlab/carpenter/dialect/sqlite"
+	"github.com/dev-cloverlab/carpenter/planner"
+)
 
 func TestCmdBuild(t *testing.T) {
 	// Write your code here
 }
+
+func TestSortTableNamesByDependency(t *testing.T) {
+	newMap := mysql.Tables{
+		makeTable("user"),
+		makeTable("user_item", "user", "item"),
+		makeTable("item", "item_category"),
+		makeTable("item_category"),
+		makeTable("tree", "tree"),
+	}.GroupByTableName()
+	oldMap := mysql.Tables{
+		makeTable("user"),
+		makeTable("user_log", "user"),
+	}.GroupByTableName()
+	tableNames := getTableNames(newMap, oldMap)
+
+	expected := []string{
+		"item_category",
+		"item",
+		"tree",
+		"user",
+		"user_item",
+		"user_log",
+	}
+	actual := sortTableNamesByDependency(tableNames, newMap, oldMap)
+	if !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("err: unexpected order returned.\nactual:\n%s\nexpected:\n%s\n", actual, expected)
+	}
+}
+
+func TestGetDeferredForeignKeys(t *testing.T) {
+	newMap := mysql.Tables{
+		makeTable("a", "b"),
+		makeTable("b", "a"),
+		makeTable("tree", "tree"),
+	}.GroupByTableName()
+	tableNames := getTableNames(newMap, map[string]*mysql.Table{})
+	deferred := getDeferredForeignKeys(tableNames, newMap, map[string]*mysql.Table{})
+	if len(deferred) != 1 || len(deferred["b"]) != 1 || deferred["b"][0].ConstraintName != "b_fk_a" {
+		t.Fatalf("err: unexpected deferred foreign keys %v", deferred)
+	}
+	// the foreign key which already exists is not deferred
+	oldMap := mysql.Tables{makeTable("b", "a")}.GroupByTableName()
+	oldMap["b"].ForeignKeys[0].ConstraintName = ""
+	if deferred := getDeferredForeignKeys(tableNames, newMap, oldMap); len(deferred) != 0 {
+		t.Fatalf("err: unexpected deferred foreign keys %v", deferred)
+	}
+}
+
+func TestSortViewNamesByDependency(t *testing.T) {
+	newMap := mysql.Views{
+		{TableName: "user_summary", ViewDefinition: "select `active_user`.`id` AS `id` from `active_user`"},
+		{TableName: "active_user", ViewDefinition: "select `user`.`id` AS `id` from `user`"},
+		{TableName: "item_view", ViewDefinition: "select `item`.`id` AS `id` from `item`"},
+	}.GroupByTableName()
+	oldMap := mysql.Views{
+		{TableName: "active_user", ViewDefinition: "select `user`.`id` AS `id` from `user`"},
+		{TableName: "old_view", ViewDefinition: "select `item_view`.`id` AS `id` from `item_view`"},
+	}.GroupByTableName()
+
+	expected := []string{
+		"active_user",
+		"item_view",
+		"old_view",
+		"user_summary",
+	}
+	actual := sortViewNamesByDependency(newMap, oldMap)
+	if !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("err: unexpected order returned.\nactual:\n%s\nexpected:\n%s\n", actual, expected)
+	}
+}
+
+func TestObjectChanges(t *testing.T) {
+	changes := &objectChanges{}
+	changes.add("a", true, false, []string{"create a"})
+	changes.add("b", false, true, []string{"drop b"})
+	changes.add("c", true, true, nil)
+	changes.add("d", true, true, []string{"drop d", "create d"})
+	changes.add("e", false, true, []string{"drop e"})
+
+	actual := []string{}
+	for _, change := range append(changes.drops, changes.creates...) {
+		actual = append(actual, change.Kind+" "+change.TableName)
+	}
+	expected := []string{"drop e", "drop b", "create a", "alter d"}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("err: unexpected changes returned.\nactual:\n%s\nexpected:\n%s\n", actual, expected)
+	}
+}
+
+func makeTable(tableName string, referencedTableNames ...string) *mysql.Table {
+	fks := mysql.ForeignKeys{}
+	for _, ref := range referencedTableNames {
+		fks = append(fks, &mysql.ForeignKey{
+			ConstraintName:        tableName + "_fk_" + ref,
+			TableName:             tableName,
+			ReferencedTableName:   ref,
+			ColumnNames:           []string{ref + "_id"},
+			ReferencedColumnNames: []string{"id"},
+		})
+	}
+	return &mysql.Table{
+		TableName:   tableName,
+		ForeignKeys: fks,
+	}
+}
+
+func TestMakeBuildChangesOrder(t *testing.T) {
+	openTestDB(t)
+	defer db.Close()
+	sqlDialect = sqlite.Dialect{}
+	schema = "main"
+	if err := execute([]string{`create table "user_log" ("id" integer not null, "user_id" integer not null)`}); err != nil {
+		t.Fatal(err)
+	}
+
+	dir, err := ioutil.TempDir("", "carpenter")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	tables := mysql.Tables{
+		makeColumnTable("user"),
+		makeColumnTable("user_item", "user", "item"),
+		makeColumnTable("item", "item_category"),
+		makeColumnTable("item_category"),
+		makeColumnTable("tree", "tree"),
+	}
+	for _, table := range tables {
+		buf, err := json.Marshal(mysql.Tables{table})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := exportJson(dir, table.TableName, buf); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	expected := []string{
+		"create item_category",
+		"create item",
+		"create tree",
+		"create user",
+		"create user_item",
+		"drop user_log",
+	}
+	var first []*planner.Change
+	for i := 0; i < 30; i++ {
+		changes, errs := makeBuildChanges(dir, true, false)
+		if len(errs) > 0 {
+			t.Fatal(errs)
+		}
+		if first == nil {
+			first = changes
+			actual := make([]string, 0, len(changes))
+			for _, change := range changes {
+				actual = append(actual, change.Kind+" "+change.TableName)
+			}
+			if !reflect.DeepEqual(actual, expected) {
+				t.Fatalf("err: unexpected order returned.\nactual:\n%s\nexpected:\n%s\n", actual, expected)
+			}
+		}
+		if !reflect.DeepEqual(changes, first) {
+			t.Fatalf("err: the order of changes differs in %d times", i+1)
+		}
+	}
+}
+
+func TestMakeBuildChangesCircularReference(t *testing.T) {
+	openTestDB(t)
+	defer db.Close()
+	sqlDialect = sqlite.Dialect{}
+	schema = "main"
+
+	dir, err := ioutil.TempDir("", "carpenter")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	buf, err := json.Marshal(mysql.Tables{
+		makeColumnTable("team", "user"),
+		makeColumnTable("user", "team"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := exportJson(dir, "tables", buf); err != nil {
+		t.Fatal(err)
+	}
+
+	changes, errs := makeBuildChanges(dir, false, false)
+	if len(errs) > 0 {
+		t.Fatal(errs)
+	}
+	actual := make([]string, 0, len(changes))
+	for _, change := range changes {
+		actual = append(actual, change.Kind+" "+change.TableName)
+	}
+	expected := []string{"create user", "create team", "alter user"}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("err: unexpected order returned.\nactual:\n%s\nexpected:\n%s\n", actual, expected)
+	}
+	if strings.Contains(strings.Join(changes[0].Queries, "\n"), "references") {
+		t.Errorf("err: the cyclic foreign key is created with the table\n%s", changes[0].Queries)
+	}
+	if err := execute((&planner.Plan{Changes: changes}).Queries()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// makeColumnTable makes a table which has the columns referring the specified tables.
+func makeColumnTable(tableName string, referencedTableNames ...string) *mysql.Table {
+	table := makeTable(tableName, referencedTableNames...)
+	table.Columns = mysql.Columns{
+		&mysql.Column{TableName: tableName, ColumnName: "id", OrdinalPosition: 1, Nullable: "NO", ColumnType: "integer", ColumnKey: "PRI"},
+	}
+	table.Indices = mysql.Indices{
+		mysql.Index{{Table: tableName, KeyName: "PRIMARY", SeqInIndex: 1, ColumnName: "id"}},
+	}
+	for i, ref := range referencedTableNames {
+		table.Columns = append(table.Columns, &mysql.Column{TableName: tableName, ColumnName: ref + "_id", OrdinalPosition: int32(i + 2), Nullable: "NO", ColumnType: "integer"})
+	}
+	return table
+}
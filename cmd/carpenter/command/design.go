@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"os"
+	"strings"
 
 	"github.com/codegangsta/cli"
 	"github.com/dev-cloverlab/carpenter/designer"
@@ -24,39 +25,69 @@ func CmdDesign(c *cli.Context) {
 	pretty := c.Bool("pretty")
 	separate := c.Bool("separate")
 
-	tables, err := designer.Export(db, schema)
+	tables, err := designer.Export(db, sqlDialect, schema)
 	if err != nil {
 		panic(fmt.Errorf("err: designer.Export failed for reason %s", err))
 	}
+	views, err := designer.ExportViews(db, sqlDialect, schema)
+	if err != nil {
+		panic(fmt.Errorf("err: designer.ExportViews failed for reason %s", err))
+	}
+	triggers, routines, err := designer.ExportRoutines(db, sqlDialect, schema)
+	if err != nil {
+		panic(fmt.Errorf("err: designer.ExportRoutines failed for reason %s", err))
+	}
+	events, err := designer.ExportEvents(db, sqlDialect, schema)
+	if err != nil {
+		panic(fmt.Errorf("err: designer.ExportEvents failed for reason %s", err))
+	}
 
+	// the objects except tables are written as an object like {"Views": [...]} to tell them from the array of tables.
+	// the files of triggers, routines and events have the suffix because they are not in the namespace of tables.
+	files := map[string]interface{}{}
 	if separate {
 		for _, table := range tables {
-			var j []byte
-			var err error
-			if pretty {
-				j, err = json.MarshalIndent(mysql.Tables{table}, "", "\t")
-			} else {
-				j, err = json.Marshal(mysql.Tables{table})
-			}
-			if err != nil {
-				panic(fmt.Errorf("err: json.MarshalIndent is fialed for reason %s", err))
-			}
-			if err := exportJson(dirPath, table.TableName, j); err != nil {
-				panic(fmt.Errorf("err: exportJson is fialed for reason %s", err))
-			}
+			files[table.TableName] = mysql.Tables{table}
+		}
+		for _, view := range views {
+			files[view.TableName] = &mysql.Schema{Views: mysql.Views{view}}
+		}
+		for _, trigger := range triggers {
+			files[trigger.TriggerName+".trigger"] = &mysql.Schema{Triggers: mysql.Triggers{trigger}}
+		}
+		for _, routine := range routines {
+			files[routine.RoutineName+"."+strings.ToLower(routine.RoutineType)] = &mysql.Schema{Routines: mysql.Routines{routine}}
+		}
+		for _, event := range events {
+			files[event.EventName+".event"] = &mysql.Schema{Events: mysql.Events{event}}
 		}
 	} else {
+		files["tables"] = tables
+		if len(views) > 0 {
+			files["views"] = &mysql.Schema{Views: views}
+		}
+		if len(triggers) > 0 {
+			files["triggers"] = &mysql.Schema{Triggers: triggers}
+		}
+		if len(routines) > 0 {
+			files["routines"] = &mysql.Schema{Routines: routines}
+		}
+		if len(events) > 0 {
+			files["events"] = &mysql.Schema{Events: events}
+		}
+	}
+	for filename, v := range files {
 		var j []byte
 		var err error
 		if pretty {
-			j, err = json.MarshalIndent(tables, "", "\t")
+			j, err = json.MarshalIndent(v, "", "\t")
 		} else {
-			j, err = json.Marshal(tables)
+			j, err = json.Marshal(v)
 		}
 		if err != nil {
 			panic(fmt.Errorf("err: json.MarshalIndent is fialed for reason %s", err))
 		}
-		if err := exportJson(dirPath, "tables", j); err != nil {
+		if err := exportJson(dirPath, filename, j); err != nil {
 			panic(fmt.Errorf("err: exportJson is fialed for reason %s", err))
 		}
 	}
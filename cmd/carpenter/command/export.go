@@ -3,14 +3,12 @@ package command
 import (
 	"database/sql"
 	"fmt"
-	"io/ioutil"
 	"os"
 	"regexp"
 	"strings"
 	"sync"
 
 	"github.com/codegangsta/cli"
-	"github.com/dev-cloverlab/carpenter/dialect/mysql"
 	"github.com/dev-cloverlab/carpenter/exporter"
 )
 
@@ -25,11 +23,17 @@ func CmdExport(c *cli.Context) {
 		}
 	}
 
+	pageSize := c.Int("page-size")
+	exporter.NullValue = c.String("null")
+	exporter.BinaryEncoding = c.String("binary-encoding")
+	if exporter.BinaryEncoding != "hex" && exporter.BinaryEncoding != "base64" {
+		panic(fmt.Errorf("err: Unsupported binary encoding `%s'", exporter.BinaryEncoding))
+	}
 	re := c.String("regexp")
 	tableNameRegexp := regexp.MustCompile(re)
-	tables, err := mysql.GetTables(db, schema)
+	tables, err := sqlDialect.GetTables(db, schema)
 	if err != nil {
-		panic(fmt.Errorf("err: mysql.GetTables failed for reason %s", err))
+		panic(fmt.Errorf("err: GetTables failed for reason %s", err))
 	}
 
 	errs := []error{}
@@ -67,12 +71,7 @@ func CmdExport(c *cli.Context) {
 		wg.Add(1)
 		go func(d *sql.DB, s, t string) {
 			defer wg.Done()
-			csv, err := exporter.Export(d, s, t)
-			if err != nil {
-				errCh <- err
-				return
-			}
-			if err := ioutil.WriteFile(fmt.Sprintf("%s%s%s.csv", dirPath, string(os.PathSeparator), t), []byte(csv), os.ModePerm); err != nil {
+			if err := exportFile(fmt.Sprintf("%s%s%s.csv", dirPath, string(os.PathSeparator), t), d, s, t, pageSize); err != nil {
 				errCh <- err
 				return
 			}
@@ -82,3 +81,15 @@ func CmdExport(c *cli.Context) {
 	wg.Wait()
 	doneCh <- true
 }
+
+func exportFile(path string, d *sql.DB, s, t string, pageSize int) error {
+	fp, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, os.ModePerm)
+	if err != nil {
+		return err
+	}
+	if err := exporter.Export(fp, d, sqlDialect, s, t, pageSize); err != nil {
+		fp.Close()
+		return fmt.Errorf("err: Export %s failed for reason %s", t, err)
+	}
+	return fp.Close()
+}
@@ -1,7 +1,143 @@
 package command
 
-import "testing"
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/dev-cloverlab/carpenter/dialect/sqlite"
+	"github.com/dev-cloverlab/carpenter/exporter"
+)
 
 func TestCmdExport(t *testing.T) {
 	// Write your code here
 }
+
+func TestExportAndImport(t *testing.T) {
+	tests := []struct {
+		name  string
+		value interface{}
+	}{
+		{"comma", "a,b"},
+		{"quote", `say "hi"`},
+		{"single quote", "it's"},
+		{"newline", "line1\nline2"},
+		{"backslash", `C:\path\`},
+		{"spaces", "  both sides  "},
+		{"empty", ""},
+		{"null", nil},
+		{"null token", "NULL"},
+		{"lower null token", "null"},
+		{"leading zero", "007"},
+		{"unicode", "日本語, テキスト"},
+		{"only quote", `"`},
+		{"sql like", "'); drop table x; --"},
+	}
+	for _, test := range tests {
+		openTestDB(t)
+		sqlDialect = sqlite.Dialect{}
+		schema = "main"
+		if err := execute([]string{
+			`create table "round_trip" ("id" integer primary key, "value" text, "score" real)`,
+			`create table "single" ("value" text unique)`,
+		}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := db.Exec(`insert into "round_trip" values (1, ?, 1.5), (2, ?, 1000000)`, test.value, test.value); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := db.Exec(`insert into "single" values (?)`, test.value); err != nil {
+			t.Fatal(err)
+		}
+
+		dir, err := ioutil.TempDir("", "carpenter")
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, table := range []string{"round_trip", "single"} {
+			if err := exportFile(filepath.Join(dir, fmt.Sprintf("%s.csv", table)), db, schema, table, 0); err != nil {
+				t.Fatal(err)
+			}
+		}
+		changes, errs := makeSeedChanges(dir, map[string][]string{"single": {"value"}}, "NULL")
+		if len(errs) > 0 {
+			t.Fatal(errs)
+		}
+		if len(changes) > 0 {
+			t.Errorf("err: import of exported %s value generates changes %q", test.name, changes[0].Queries)
+		}
+		os.RemoveAll(dir)
+		db.Close()
+	}
+}
+
+func TestExportAndImportBinary(t *testing.T) {
+	defer func() { exporter.BinaryEncoding = "hex" }()
+	for _, encoding := range []string{"hex", "base64"} {
+		openTestDB(t)
+		sqlDialect = sqlite.Dialect{}
+		schema = "main"
+		if err := execute([]string{`create table "binary" ("id" integer primary key, "data" blob)`}); err != nil {
+			t.Fatal(err)
+		}
+		for i, data := range [][]byte{{0x00, 0xff, '"', ',', '\n', 0x80}, {}, nil} {
+			if _, err := db.Exec(`insert into "binary" values (?, ?)`, i+1, data); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		dir, err := ioutil.TempDir("", "carpenter")
+		if err != nil {
+			t.Fatal(err)
+		}
+		path := filepath.Join(dir, "binary.csv")
+		exporter.BinaryEncoding = encoding
+		if err := exportFile(path, db, schema, "binary", 0); err != nil {
+			t.Fatal(err)
+		}
+		buf, err := ioutil.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected := "id,data\n1,0x00ff222c0a80\n2,0x\n3,NULL\n"
+		if encoding == "base64" {
+			expected = "id,data\n1,base64:AP8iLAqA\n2,base64:\n3,NULL\n"
+		}
+		if string(buf) != expected {
+			t.Errorf("err: unexpected CSV by %s.\nexpected %q\nbut actual %q", encoding, expected, string(buf))
+		}
+		changes, errs := makeSeedChanges(dir, nil, "NULL")
+		if len(errs) > 0 {
+			t.Fatal(errs)
+		}
+		if len(changes) > 0 {
+			t.Errorf("err: import of exported binary values generates changes %q", changes[0].Queries)
+		}
+
+		if err := ioutil.WriteFile(path, []byte("id,data\n1,0x00fe\n2,0x\n3,NULL\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		changes, errs = makeSeedChanges(dir, nil, "NULL")
+		if len(errs) > 0 {
+			t.Fatal(errs)
+		}
+		if len(changes) != 1 {
+			t.Fatalf("err: unexpected changes %+v", changes)
+		}
+		if err := executeSeedChanges(changes, false, false); err != nil {
+			t.Fatal(err)
+		}
+		var data []byte
+		if err := db.QueryRow(`select "data" from "binary" where "id" = 1`).Scan(&data); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(data, []byte{0x00, 0xfe}) {
+			t.Errorf("err: unexpected binary %x", data)
+		}
+		os.RemoveAll(dir)
+		db.Close()
+	}
+}
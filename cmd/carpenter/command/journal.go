@@ -0,0 +1,116 @@
+package command
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// defaultJournalFmt is the journal file name of each schema when --journal option is not set.
+const defaultJournalFmt = "carpenter_build_%s.journal"
+
+// journal records the queries of a build and how many of them have been applied,
+// so that a rerun after a failure resumes from the failed statement.
+type journal struct {
+	Target  journalTarget
+	Queries []string
+	Applied int
+}
+
+// journalTarget identifies the database and the JSON files which the queries of a journal are made for.
+// The data source is hashed not to leave the password in the journal file.
+type journalTarget struct {
+	Driver     string
+	Schema     string
+	DataSource string
+	Dir        string
+}
+
+func newJournalTarget(driver, schema, dataSource, dir string) (journalTarget, error) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return journalTarget{}, fmt.Errorf("err: filepath.Abs %s failed for reason %s", dir, err)
+	}
+	sum := sha256.Sum256([]byte(dataSource))
+	return journalTarget{
+		Driver:     driver,
+		Schema:     schema,
+		DataSource: hex.EncodeToString(sum[:]),
+		Dir:        abs,
+	}, nil
+}
+
+// loadJournal returns nil when the journal file does not exist.
+// It refuses the journal which is recorded for another target, because its queries must not be applied to the database.
+func loadJournal(path string, target journalTarget) (*journal, error) {
+	buf, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("err: ioutil.ReadFile %s failed for reason %s", path, err)
+	}
+	j := &journal{}
+	if err := json.Unmarshal(buf, j); err != nil {
+		return nil, fmt.Errorf("err: json.Unmarshal %s failed for reason %s", path, err)
+	}
+	if j.Applied < 0 || j.Applied > len(j.Queries) {
+		return nil, fmt.Errorf("err: broken journal %s applied %d of %d queries", path, j.Applied, len(j.Queries))
+	}
+	if j.Target != target {
+		return nil, fmt.Errorf("err: journal %s is recorded for schema `%s' of driver `%s' with %s, remove it or specify another journal by --journal option", path, j.Target.Schema, j.Target.Driver, j.Target.Dir)
+	}
+	return j, nil
+}
+
+// save writes the journal to a temporary file and renames it so that a broken journal is never left.
+func (m *journal) save(path string) error {
+	buf, err := json.MarshalIndent(m, "", "\t")
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := ioutil.WriteFile(tmp, buf, 0644); err != nil {
+		return fmt.Errorf("err: ioutil.WriteFile %s failed for reason %s", tmp, err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("err: os.Rename %s failed for reason %s", tmp, err)
+	}
+	return nil
+}
+
+func (m *journal) appliedQueries() []string {
+	return m.Queries[:m.Applied]
+}
+
+func (m *journal) remainingQueries() []string {
+	return m.Queries[m.Applied:]
+}
+
+// executeWithJournal executes the remaining queries one by one and records the progress after each of them.
+// The journal is removed when all of the queries are applied.
+func executeWithJournal(path string, j *journal) error {
+	if dryrun {
+		return execute(j.remainingQueries())
+	}
+	if err := j.save(path); err != nil {
+		return err
+	}
+	for _, query := range j.remainingQueries() {
+		if err := execute([]string{query}); err != nil {
+			return fmt.Errorf("%s\napplied %d of %d queries, rerun to resume from the failed query (journal %s)", err, j.Applied, len(j.Queries), path)
+		}
+		j.Applied++
+		if err := j.save(path); err != nil {
+			return err
+		}
+	}
+	if err := os.Remove(path); err != nil {
+		return fmt.Errorf("err: os.Remove %s failed for reason %s", path, err)
+	}
+	return nil
+}
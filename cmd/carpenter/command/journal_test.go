@@ -0,0 +1,113 @@
+package command
+
+import (
+	"database/sql"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func openTestDB(t *testing.T) {
+	var err error
+	db, err = sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// each connection of in-memory database has its own database
+	db.SetMaxOpenConns(1)
+}
+
+func countRows(t *testing.T, table string) int {
+	var cnt int
+	if err := db.QueryRow("select count(*) from " + table).Scan(&cnt); err != nil {
+		t.Fatal(err)
+	}
+	return cnt
+}
+
+func TestExecuteWithJournal(t *testing.T) {
+	openTestDB(t)
+	defer db.Close()
+	dir, err := ioutil.TempDir("", "carpenter")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "build.journal")
+	target, err := newJournalTarget("sqlite3", "main", "test.db", dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	j := &journal{Target: target, Queries: []string{
+		"create table a (id integer)",
+		"insert into a values (1)",
+		"insert into b values (1)",
+		"insert into a values (2)",
+	}}
+	if err := executeWithJournal(path, j); err == nil {
+		t.Fatal("err: executeWithJournal must fail")
+	}
+	for _, other := range []journalTarget{
+		{Driver: "mysql", Schema: target.Schema, DataSource: target.DataSource, Dir: target.Dir},
+		{Driver: target.Driver, Schema: "other", DataSource: target.DataSource, Dir: target.Dir},
+		{Driver: target.Driver, Schema: target.Schema, DataSource: "other", Dir: target.Dir},
+		{Driver: target.Driver, Schema: target.Schema, DataSource: target.DataSource, Dir: os.TempDir()},
+	} {
+		if _, err := loadJournal(path, other); err == nil {
+			t.Errorf("err: loadJournal must refuse the journal for %+v", other)
+		}
+	}
+	resumed, err := loadJournal(path, target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(resumed, j) || resumed.Applied != 2 {
+		t.Fatalf("err: unexpected journal %+v", resumed)
+	}
+	if expected := j.Queries[:2]; !reflect.DeepEqual(resumed.appliedQueries(), expected) {
+		t.Errorf("err: unexpected applied queries %v", resumed.appliedQueries())
+	}
+
+	if _, err := db.Exec("create table b (id integer)"); err != nil {
+		t.Fatal(err)
+	}
+	if err := executeWithJournal(path, resumed); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("err: journal is not removed %v", err)
+	}
+	if cnt := countRows(t, "a"); cnt != 2 {
+		t.Errorf("err: unexpected count %d", cnt)
+	}
+	if cnt := countRows(t, "b"); cnt != 1 {
+		t.Errorf("err: unexpected count %d", cnt)
+	}
+}
+
+func TestExecuteInTransaction(t *testing.T) {
+	openTestDB(t)
+	defer db.Close()
+	if err := execute([]string{"create table a (id integer primary key)"}); err != nil {
+		t.Fatal(err)
+	}
+	err := executeInTransaction([]string{
+		"insert into a values (1)",
+		"insert into a values (1)",
+	}, false)
+	if err == nil {
+		t.Fatal("err: executeInTransaction must fail")
+	}
+	if cnt := countRows(t, "a"); cnt != 0 {
+		t.Errorf("err: transaction is not rolled back %d", cnt)
+	}
+	if err := executeInTransaction([]string{"insert into a values (1)", "insert into a values (2)"}, false); err != nil {
+		t.Fatal(err)
+	}
+	if cnt := countRows(t, "a"); cnt != 2 {
+		t.Errorf("err: unexpected count %d", cnt)
+	}
+}
@@ -0,0 +1,104 @@
+package command
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/codegangsta/cli"
+	"github.com/dev-cloverlab/carpenter/dialect/mysql"
+	"github.com/dev-cloverlab/carpenter/partitioner"
+)
+
+func CmdPartition(c *cli.Context) {
+	policies, err := getPartitionPolicies(c.StringSlice("retention"), c.String("config"), c.Int("ahead"))
+	if err != nil {
+		panic(err)
+	}
+	queries, err := makePartitionQueries(policies, time.Now())
+	if err != nil {
+		panic(err)
+	}
+	if err := execute(queries); err != nil {
+		panic(fmt.Errorf("err: execute failed for reason %s", err))
+	}
+}
+
+// getPartitionPolicies returns the policies for each table specified by the config file and the retention options like 'table=30'.
+// The retention options take priority over the config file, and ahead is used when the policy does not have it,
+// so that Ahead 0 in the config file is kept.
+func getPartitionPolicies(retentionOptions []string, configFile string, ahead int) (map[string]partitioner.Policy, error) {
+	policies := map[string]partitioner.Policy{}
+	if configFile != "" {
+		buf, err := ioutil.ReadFile(configFile)
+		if err != nil {
+			return nil, fmt.Errorf("err: ioutil.ReadFile %s failed for reason %s", configFile, err)
+		}
+		raws := map[string]json.RawMessage{}
+		if err := json.Unmarshal(buf, &raws); err != nil {
+			return nil, fmt.Errorf("err: json.Unmarshal %s failed for reason %s", configFile, err)
+		}
+		for tableName, raw := range raws {
+			// the fields which the policy does not have are left as the defaults
+			policy := partitioner.Policy{Ahead: ahead}
+			if err := json.Unmarshal(raw, &policy); err != nil {
+				return nil, fmt.Errorf("err: json.Unmarshal %s failed for reason %s", configFile, err)
+			}
+			policies[tableName] = policy
+		}
+	}
+	for _, option := range retentionOptions {
+		pos := strings.Index(option, "=")
+		if pos <= 0 || pos == len(option)-1 {
+			return nil, fmt.Errorf("err: Invalid retention option `%s', specify like `table=30'", option)
+		}
+		days, err := strconv.Atoi(option[pos+1:])
+		if err != nil {
+			return nil, fmt.Errorf("err: Invalid retention option `%s' for reason %s", option, err)
+		}
+		policy, ok := policies[option[:pos]]
+		if !ok {
+			policy = partitioner.Policy{Ahead: ahead}
+		}
+		policy.Retention = days
+		policies[option[:pos]] = policy
+	}
+	if len(policies) <= 0 {
+		return nil, fmt.Errorf("err: Specify the tables by `--retention' or `--config' option")
+	}
+	return policies, nil
+}
+
+// makePartitionQueries returns the statements to rotate the partitions of the tables in the order of the table name.
+func makePartitionQueries(policies map[string]partitioner.Policy, now time.Time) ([]string, error) {
+	if sqlDialect.DriverName() != "mysql" {
+		return nil, fmt.Errorf("err: partition command only supports mysql driver")
+	}
+	tableNames := make([]string, 0, len(policies))
+	for tableName := range policies {
+		tableNames = append(tableNames, tableName)
+	}
+	sort.Strings(tableNames)
+	tables, err := mysql.GetTables(db, schema, tableNames...)
+	if err != nil {
+		return nil, err
+	}
+	tableMap := tables.GroupByTableName()
+	queries := []string{}
+	for _, tableName := range tableNames {
+		table, ok := tableMap[tableName]
+		if !ok {
+			return nil, fmt.Errorf("err: Table `%s' is not found in schema `%s'", tableName, schema)
+		}
+		q, err := partitioner.Rotate(table, policies[tableName], now)
+		if err != nil {
+			return nil, err
+		}
+		queries = append(queries, q...)
+	}
+	return queries, nil
+}
@@ -0,0 +1,45 @@
+package command
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/dev-cloverlab/carpenter/partitioner"
+)
+
+func TestGetPartitionPolicies(t *testing.T) {
+	dir, err := ioutil.TempDir("", "carpenter")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	configFile := filepath.Join(dir, "partitions.json")
+	if err := ioutil.WriteFile(configFile, []byte(`{"access_log": {"Retention": 30, "Ahead": 3}, "error_log": {"Retention": 90}, "audit_log": {"Retention": 365, "Ahead": 0}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	policies, err := getPartitionPolicies([]string{"error_log=14", "event_log=7"}, configFile, 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]partitioner.Policy{
+		"access_log": {Retention: 30, Ahead: 3},
+		"audit_log":  {Retention: 365, Ahead: 0},
+		"error_log":  {Retention: 14, Ahead: 7},
+		"event_log":  {Retention: 7, Ahead: 7},
+	}
+	if !reflect.DeepEqual(policies, expected) {
+		t.Errorf("err: unexpected policies %v", policies)
+	}
+	for _, option := range []string{"access_log", "=30", "access_log=", "access_log=month"} {
+		if _, err := getPartitionPolicies([]string{option}, "", 7); err == nil {
+			t.Errorf("err: invalid retention option `%s' is accepted", option)
+		}
+	}
+	if _, err := getPartitionPolicies(nil, "", 7); err == nil {
+		t.Errorf("err: no table is accepted")
+	}
+}
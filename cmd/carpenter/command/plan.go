@@ -0,0 +1,147 @@
+package command
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/codegangsta/cli"
+	"github.com/dev-cloverlab/carpenter/designer"
+	"github.com/dev-cloverlab/carpenter/dialect/mysql"
+	"github.com/dev-cloverlab/carpenter/planner"
+	"github.com/dev-cloverlab/carpenter/seeder"
+)
+
+var planVersionFmt string = "20060102150405"
+
+func CmdPlan(c *cli.Context) {
+	// Write your code here
+	dirPath := c.String("dir")
+	dataDirPath := c.String("data-dir")
+	if dirPath == "" && dataDirPath == "" {
+		panic(fmt.Errorf("err: Specify `--dir' or `--data-dir' option"))
+	}
+	outPath := c.String("out")
+	if outPath == "" {
+		var err error
+		outPath, err = os.Getwd()
+		if err != nil {
+			panic(fmt.Errorf("err: os.Getwd failed for reason %s", err))
+		}
+	}
+
+	plan := &planner.Plan{
+		Version: time.Now().Format(planVersionFmt),
+	}
+	if dirPath != "" {
+		changes, errs := makeBuildChanges(dirPath, c.Bool("with-drop"), c.Bool("detect-rename"))
+		if len(errs) > 0 {
+			panic(fmt.Errorf("err: makeBuildChanges failed for reason\n%s", strings.Join(getErrorMessages(errs), "\n")))
+		}
+		plan.Changes = append(plan.Changes, changes...)
+	}
+	if dataDirPath != "" {
+		keys, err := getSeedKeys(c.StringSlice("key"), c.String("key-file"))
+		if err != nil {
+			panic(err)
+		}
+		changes, errs := makeSeedChanges(dataDirPath, keys, c.String("null"))
+		if len(errs) > 0 {
+			panic(fmt.Errorf("err: makeSeedChanges failed for reason\n%s", strings.Join(getErrorMessages(errs), "\n")))
+		}
+		plan.Changes = append(plan.Changes, changes...)
+	}
+	checksum, err := getSchemaChecksum(plan.DataTableNames())
+	if err != nil {
+		panic(err)
+	}
+	plan.Checksum = checksum
+
+	names, err := plan.Write(outPath, c.Bool("separate"))
+	if err != nil {
+		panic(fmt.Errorf("err: plan.Write failed for reason %s", err))
+	}
+	for _, name := range names {
+		fmt.Println(name)
+	}
+}
+
+func CmdApply(c *cli.Context) {
+	// Write your code here
+	path := c.String("dir")
+	if path == "" {
+		panic(fmt.Errorf("err: Specify required `--dir' option"))
+	}
+	// only MySQL escapes quotes by backslashes in strings, whose connections do not have NO_BACKSLASH_ESCAPES mode
+	plan, err := planner.Read(path, sqlDialect.DriverName() == "mysql")
+	if err != nil {
+		panic(fmt.Errorf("err: planner.Read failed for reason %s", err))
+	}
+	if err := applyPlan(plan, c.Bool("ignore-foreign-key")); err != nil {
+		panic(err)
+	}
+}
+
+// applyPlan executes the changes of the plan, when the schema and the rows of the seeded tables are not changed since the plan was made.
+func applyPlan(plan *planner.Plan, ignoreForeignKey bool) error {
+	checksum, err := getSchemaChecksum(plan.DataTableNames())
+	if err != nil {
+		return err
+	}
+	if checksum != plan.Checksum {
+		return fmt.Errorf("err: The schema has been changed since the plan %s was made. Make the plan again", plan.Version)
+	}
+	for _, change := range plan.Changes {
+		if change.Kind == planner.KindData {
+			err = executeSeedChanges([]*planner.Change{change}, false, ignoreForeignKey)
+		} else {
+			err = execute(change.Queries)
+		}
+		if err != nil {
+			return fmt.Errorf("err: execute %s of %s failed for reason %s", change.Kind, change.TableName, err)
+		}
+	}
+	return nil
+}
+
+// getSchemaChecksum returns the checksum of all objects of the schema and the rows of the specified tables.
+func getSchemaChecksum(dataTableNames []string) (string, error) {
+	tables, err := sqlDialect.GetTables(db, schema)
+	if err != nil {
+		return "", fmt.Errorf("err: GetTables failed for reason %s", err)
+	}
+	views, err := designer.ExportViews(db, sqlDialect, schema)
+	if err != nil {
+		return "", fmt.Errorf("err: designer.ExportViews failed for reason %s", err)
+	}
+	triggers, routines, err := designer.ExportRoutines(db, sqlDialect, schema)
+	if err != nil {
+		return "", fmt.Errorf("err: designer.ExportRoutines failed for reason %s", err)
+	}
+	events, err := designer.ExportEvents(db, sqlDialect, schema)
+	if err != nil {
+		return "", fmt.Errorf("err: designer.ExportEvents failed for reason %s", err)
+	}
+	tableMap := tables.GroupByTableName()
+	chunks := make([]*mysql.Chunk, 0, len(dataTableNames))
+	for _, tableName := range dataTableNames {
+		table, ok := tableMap[tableName]
+		if !ok {
+			// the table definitions already differ
+			continue
+		}
+		cnk, err := seeder.GetChunk(db, sqlDialect, table)
+		if err != nil {
+			return "", fmt.Errorf("err: GetChunk %s failed for reason %s", tableName, err)
+		}
+		chunks = append(chunks, cnk)
+	}
+	return planner.Checksum(&mysql.Schema{
+		Tables:   tables,
+		Views:    views,
+		Triggers: triggers,
+		Routines: routines,
+		Events:   events,
+	}, chunks)
+}
@@ -0,0 +1,115 @@
+package command
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/dev-cloverlab/carpenter/dialect/mysql"
+	"github.com/dev-cloverlab/carpenter/dialect/sqlite"
+	"github.com/dev-cloverlab/carpenter/planner"
+)
+
+// objectDialect reads the views and the triggers of SQLite, which are not managed by sqlite.Dialect.
+type objectDialect struct {
+	sqlite.Dialect
+}
+
+func (objectDialect) GetViews(db *sql.DB, schema string) (mysql.Views, error) {
+	rows, err := db.Query(`select "name", "sql" from "sqlite_master" where "type" = 'view' order by "name"`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	views := mysql.Views{}
+	for rows.Next() {
+		view := &mysql.View{TableSchema: schema}
+		if err := rows.Scan(&view.TableName, &view.ViewDefinition); err != nil {
+			return nil, err
+		}
+		views = append(views, view)
+	}
+	return views, rows.Err()
+}
+
+func (objectDialect) GetTriggers(db *sql.DB, schema string) (mysql.Triggers, error) {
+	rows, err := db.Query(`select "name", "tbl_name", "sql" from "sqlite_master" where "type" = 'trigger' order by "name"`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	triggers := mysql.Triggers{}
+	for rows.Next() {
+		trigger := &mysql.Trigger{TriggerSchema: schema}
+		if err := rows.Scan(&trigger.TriggerName, &trigger.EventObjectTable, &trigger.ActionStatement); err != nil {
+			return nil, err
+		}
+		triggers = append(triggers, trigger)
+	}
+	return triggers, rows.Err()
+}
+
+func (objectDialect) GetRoutines(db *sql.DB, schema string) (mysql.Routines, error) {
+	return mysql.Routines{}, nil
+}
+
+func (objectDialect) ToCreateViewSQL(view *mysql.View) string          { return "" }
+func (objectDialect) ToDropViewSQL(view *mysql.View) string            { return "" }
+func (objectDialect) ToCreateTriggerSQL(trigger *mysql.Trigger) string { return "" }
+func (objectDialect) ToDropTriggerSQL(trigger *mysql.Trigger) string   { return "" }
+func (objectDialect) ToCreateRoutineSQL(routine *mysql.Routine) string { return "" }
+func (objectDialect) ToDropRoutineSQL(routine *mysql.Routine) string   { return "" }
+
+func TestApplyPlanChecksum(t *testing.T) {
+	openTestDB(t)
+	defer db.Close()
+	sqlDialect = objectDialect{}
+	schema = "main"
+	if err := execute([]string{
+		`create table "user" ("id" integer primary key, "name" text)`,
+		`insert into "user" values (1, 'a')`,
+		`create view "user_name" as select "name" from "user"`,
+		`create trigger "user_insert" after insert on "user" begin select 1; end`,
+	}); err != nil {
+		t.Fatal(err)
+	}
+	plan := &planner.Plan{Version: "1", Changes: []*planner.Change{
+		{TableName: "user", Kind: planner.KindData, Queries: []string{`insert into "user" values (2, 'b')`}},
+	}}
+
+	tests := []struct {
+		name    string
+		queries []string
+	}{
+		{"view", []string{`drop view "user_name"`, `create view "user_name" as select "id", "name" from "user"`}},
+		{"trigger", []string{`drop trigger "user_insert"`, `create trigger "user_insert" after insert on "user" begin select 2; end`}},
+		{"rows", []string{`update "user" set "name" = 'x' where "id" = 1`}},
+	}
+	for _, test := range tests {
+		checksum, err := getSchemaChecksum(plan.DataTableNames())
+		if err != nil {
+			t.Fatal(err)
+		}
+		plan.Checksum = checksum
+		if err := execute(test.queries); err != nil {
+			t.Fatal(err)
+		}
+		if err := applyPlan(plan, false); err == nil {
+			t.Errorf("err: applyPlan must fail after the %s is changed", test.name)
+		}
+	}
+	if cnt := countRows(t, `"user"`); cnt != 1 {
+		t.Fatalf("err: the refused plan is applied %d", cnt)
+	}
+
+	checksum, err := getSchemaChecksum(plan.DataTableNames())
+	if err != nil {
+		t.Fatal(err)
+	}
+	plan.Checksum = checksum
+	if err := applyPlan(plan, false); err != nil {
+		t.Fatal(err)
+	}
+	if cnt := countRows(t, `"user"`); cnt != 2 {
+		t.Errorf("err: unexpected count %d", cnt)
+	}
+}
@@ -1,59 +1,169 @@
 package command
 
 import (
+	"bytes"
 	"encoding/csv"
+	"encoding/json"
 	"fmt"
 	"io"
-	"os"
-	"strconv"
+	"io/ioutil"
+	"sort"
 	"strings"
 	"sync"
 
 	"github.com/codegangsta/cli"
 	"github.com/dev-cloverlab/carpenter/dialect/mysql"
+	"github.com/dev-cloverlab/carpenter/planner"
 	"github.com/dev-cloverlab/carpenter/seeder"
 )
 
 func CmdSeed(c *cli.Context) {
 	// Write your code here
 	dirPath := c.String("dir")
-	queries, errs := makeSeedQueries(dirPath, nil)
+	keys, err := getSeedKeys(c.StringSlice("key"), c.String("key-file"))
+	if err != nil {
+		panic(err)
+	}
+	changes, errs := makeSeedChanges(dirPath, keys, c.String("null"))
 	if len(errs) > 0 {
-		panic(fmt.Errorf("err: makeSeedQueries failed for reason\n%s", strings.Join(getErrorMessages(errs), "\n")))
+		panic(fmt.Errorf("err: makeSeedChanges failed for reason\n%s", strings.Join(getErrorMessages(errs), "\n")))
 	}
 
-	ignoreForeignKey := c.Bool("ignore-foreign-key")
-	if ignoreForeignKey {
-		queries = append([]string{mysql.ForeignKeyCheck(false)}, queries...)
+	if err := executeSeedChanges(changes, c.Bool("single-transaction"), c.Bool("ignore-foreign-key")); err != nil {
+		panic(fmt.Errorf("err: execute failed for reason %s", err))
+	}
+}
 
-		defer func() {
-			if err := execute([]string{mysql.ForeignKeyCheck(true)}); err != nil {
-				panic(fmt.Errorf("err: execute failed for reason %s", err))
-			}
-		}()
+// executeSeedChanges executes each change in a transaction, or all changes in a single transaction.
+func executeSeedChanges(changes []*planner.Change, singleTransaction, ignoreForeignKey bool) error {
+	batches := make([][]string, 0, len(changes))
+	for _, change := range changes {
+		batches = append(batches, change.Queries)
+	}
+	if singleTransaction {
+		queries := []string{}
+		for _, batch := range batches {
+			queries = append(queries, batch...)
+		}
+		batches = [][]string{queries}
 	}
 
-	if err := execute(queries); err != nil {
-		panic(fmt.Errorf("err: execute failed for reason %s", err))
+	for _, queries := range batches {
+		if err := executeInTransaction(queries, ignoreForeignKey); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// getSeedKeys returns the key columns for each table specified by the key file and the key options like 'table=col1,col2'.
+// The key options take priority over the key file.
+func getSeedKeys(keyOptions []string, keyFile string) (map[string][]string, error) {
+	keys := map[string][]string{}
+	if keyFile != "" {
+		buf, err := ioutil.ReadFile(keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("err: ioutil.ReadFile %s failed for reason %s", keyFile, err)
+		}
+		if err := json.Unmarshal(buf, &keys); err != nil {
+			return nil, fmt.Errorf("err: json.Unmarshal %s failed for reason %s", keyFile, err)
+		}
+	}
+	for _, option := range keyOptions {
+		pos := strings.Index(option, "=")
+		if pos <= 0 || pos == len(option)-1 {
+			return nil, fmt.Errorf("err: Invalid key option `%s', specify like `table=col1,col2'", option)
+		}
+		keys[option[:pos]] = strings.Split(option[pos+1:], ",")
+	}
+	return keys, nil
+}
+
+// resolveSeedKeys returns the key columns for each table, which are the specified ones or the primary key of the table.
+// It fails with the names of all tables that have neither of them,
+// and of all tables whose specified key is neither the primary key nor a unique key,
+// because the rows are replaced or upserted by the key.
+func resolveSeedKeys(tableNames []string, keys map[string][]string) (map[string][]string, error) {
+	resolved := make(map[string][]string, len(tableNames))
+	missing := []string{}
+	notUnique := []string{}
+	for _, tableName := range tableNames {
+		if k, ok := keys[tableName]; ok && len(k) > 0 {
+			unique, err := seeder.IsUniqueKeyColumnNames(db, sqlDialect, tableName, k)
+			if err != nil {
+				return nil, err
+			}
+			if !unique {
+				notUnique = append(notUnique, tableName)
+				continue
+			}
+			resolved[tableName] = k
+			continue
+		}
+		k, err := seeder.GetKeyColumnNames(db, sqlDialect, tableName)
+		if err != nil {
+			return nil, err
+		}
+		if len(k) <= 0 {
+			missing = append(missing, tableName)
+			continue
+		}
+		resolved[tableName] = k
+	}
+	if len(notUnique) > 0 {
+		return nil, fmt.Errorf("err: The specified keys of tables %s are neither primary key nor unique key. Add the unique key or specify another key", strings.Join(notUnique, ", "))
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("err: Tables %s have neither the specified key nor primary key. Specify them by `--key' or `--key-file' option", strings.Join(missing, ", "))
 	}
+	return resolved, nil
 }
 
-func makeSeedQueries(path string, colName *string) (queries []string, errs []error) {
+// makeSeedChanges returns the changes of the tables sorted by the dependency and the table name.
+// The fields of CSV files are converted by the types of the columns, and the fields equal to nullValue are NULL.
+func makeSeedChanges(path string, keys map[string][]string, nullValue string) (changes []*planner.Change, errs []error) {
 	files, err := walk(path, ".csv")
 	if err != nil {
 		return nil, []error{err}
 	}
+	tableNames := make([]string, 0, len(files))
+	for tableName := range files {
+		tableNames = append(tableNames, tableName)
+	}
+	sort.Strings(tableNames)
+	keys, err = resolveSeedKeys(tableNames, keys)
+	if err != nil {
+		return nil, []error{err}
+	}
+	tables, err := sqlDialect.GetTables(db, schema)
+	if err != nil {
+		return nil, []error{err}
+	}
+	tableMap := tables.GroupByTableName()
+	for _, tableName := range tableNames {
+		if _, ok := tableMap[tableName]; !ok {
+			errs = append(errs, fmt.Errorf("err: Table `%s' is not found", tableName))
+		}
+	}
+	if len(errs) > 0 {
+		return nil, errs
+	}
 
+	type result struct {
+		tableName string
+		queries   []string
+	}
 	errCh := make(chan error)
-	sqlCh := make(chan []string)
+	sqlCh := make(chan result)
 	doneCh := make(chan bool)
+	tableQueries := map[string][]string{}
 	go func() {
 		for {
 			select {
 			case err := <-errCh:
 				errs = append(errs, err)
-			case query := <-sqlCh:
-				queries = append(queries, query...)
+			case r := <-sqlCh:
+				tableQueries[r.tableName] = r.queries
 			case <-doneCh:
 				return
 			}
@@ -63,7 +173,7 @@ func makeSeedQueries(path string, colName *string) (queries []string, errs []err
 	wg := &sync.WaitGroup{}
 	for tableName, file := range files {
 		wg.Add(1)
-		go func(t string, fs []string, c *string) {
+		go func(t string, fs []string, table *mysql.Table, keys []string) {
 			defer wg.Done()
 
 			var colNames []string
@@ -71,7 +181,7 @@ func makeSeedQueries(path string, colName *string) (queries []string, errs []err
 			seeds := mysql.Seeds{}
 			for _, f := range fs {
 				var s mysql.Seeds
-				colNames, s, err = parseCSV(t, f)
+				colNames, s, err = parseCSV(f, table.Columns, nullValue)
 				if err != nil {
 					errCh <- fmt.Errorf("err: parseCSV %s failed for reason %s", t, err)
 					return
@@ -79,35 +189,64 @@ func makeSeedQueries(path string, colName *string) (queries []string, errs []err
 				seeds = append(seeds, s...)
 			}
 			new := makeChunk(t, colNames, seeds)
-			old, err := mysql.GetChunk(db, t, c)
+			old, err := seeder.GetChunk(db, sqlDialect, table)
 			if err != nil {
-				errCh <- fmt.Errorf("err: mysql.GetChunk %s failed for reason %s", t, err)
+				errCh <- fmt.Errorf("err: GetChunk %s failed for reason %s", t, err)
 				return
 			}
-			queries, err := seeder.Seed(db, old, new, c)
+			queries, err := seeder.Seed(db, sqlDialect, old, new, keys)
 			if err != nil {
 				errCh <- fmt.Errorf("err: seeder.Seed %s failed for reason %s", t, err)
+				return
 			}
-			sqlCh <- queries
-		}(tableName, file, colName)
+			sqlCh <- result{tableName: t, queries: queries}
+		}(tableName, file, tableMap[tableName], keys[tableName])
 	}
 	wg.Wait()
 
 	doneCh <- true
 
-	return queries, errs
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	// rows of referenced tables have to be inserted before the referring rows
+	tableNames = make([]string, 0, len(tableQueries))
+	for tableName := range tableQueries {
+		tableNames = append(tableNames, tableName)
+	}
+	for _, tableName := range sortTableNamesByDependency(tableNames, tableMap, map[string]*mysql.Table{}) {
+		if len(tableQueries[tableName]) > 0 {
+			changes = append(changes, &planner.Change{TableName: tableName, Kind: planner.KindData, Queries: tableQueries[tableName]})
+		}
+	}
+
+	return changes, errs
 }
 
-func parseCSV(tableName, filename string) (columnNames []string, seeds mysql.Seeds, err error) {
-	fp, err := os.Open(filename)
+// parseCSV reads the CSV file whose first line is the column names, and converts the fields by the types of the columns.
+// The fields of the generated columns are skipped because their values can not be written.
+// Only the unquoted field equal to nullValue is NULL, and the quoted one is the string.
+func parseCSV(filename string, columns mysql.Columns, nullValue string) (columnNames []string, seeds mysql.Seeds, err error) {
+	// the whole file is read to look at the raw fields, because encoding/csv reader does not tell whether they are quoted
+	buf, err := ioutil.ReadFile(filename)
 	if err != nil {
 		return nil, nil, err
 	}
-	defer fp.Close()
+	lineStarts := []int{0}
+	for i, b := range buf {
+		if b == '\n' {
+			lineStarts = append(lineStarts, i+1)
+		}
+	}
 
-	reader := csv.NewReader(fp)
+	reader := csv.NewReader(bytes.NewReader(buf))
+	// the files written by hand or by the old export may have bare quotes
 	reader.LazyQuotes = true
 
+	columnMap := columns.GroupByColumnName()
+	var fieldColumns mysql.Columns
+	var fieldIdxs []int
+	header := true
 	for {
 		record, err := reader.Read()
 		if err == io.EOF {
@@ -116,32 +255,57 @@ func parseCSV(tableName, filename string) (columnNames []string, seeds mysql.See
 		if err != nil {
 			return nil, nil, err
 		}
-		if len(columnNames) <= 0 {
-			columnNames = record
-		} else {
-			columnData := make([]interface{}, 0, len(record))
-			for _, r := range record {
-				var v interface{}
-				var err error
-				if r == "NULL" || r == "null" || r == "Null" {
-					v = nil
-				} else if v, err = strconv.ParseFloat(r, 64); err != nil {
-					v = r
-				} else {
-					if len(string(r)) > 1 && string(string(r)[0]) == "0" {
-						v = string(r)
-					}
+		if header {
+			header = false
+			columnNames = make([]string, 0, len(record))
+			fieldColumns = make(mysql.Columns, 0, len(record))
+			fieldIdxs = make([]int, 0, len(record))
+			for i, name := range record {
+				column, ok := columnMap[name]
+				if !ok {
+					line, _ := reader.FieldPos(i)
+					return nil, nil, fmt.Errorf("%s:%d: column `%s' is not found in the table", filename, line, name)
+				}
+				if column.IsGenerated() {
+					continue
 				}
-				columnData = append(columnData, v)
+				columnNames = append(columnNames, name)
+				fieldColumns = append(fieldColumns, column)
+				fieldIdxs = append(fieldIdxs, i)
 			}
-			seeds = append(seeds, mysql.Seed{
-				ColumnData: columnData,
-			})
+			continue
 		}
+		columnData := make([]interface{}, 0, len(fieldIdxs))
+		for j, i := range fieldIdxs {
+			r := record[i]
+			if r == nullValue && !isQuotedField(buf, lineStarts, reader, i) {
+				columnData = append(columnData, nil)
+				continue
+			}
+			v, err := fieldColumns[j].ParseValue(r)
+			if err != nil {
+				line, _ := reader.FieldPos(i)
+				return nil, nil, fmt.Errorf("%s:%d: column `%s': %s", filename, line, columnNames[j], err)
+			}
+			columnData = append(columnData, v)
+		}
+		seeds = append(seeds, mysql.Seed{
+			ColumnData: columnData,
+		})
 	}
 	return columnNames, seeds, nil
 }
 
+// isQuotedField reports whether the field of the record just read begins with a quote.
+func isQuotedField(buf []byte, lineStarts []int, reader *csv.Reader, field int) bool {
+	line, column := reader.FieldPos(field)
+	if line <= 0 || line > len(lineStarts) {
+		return false
+	}
+	pos := lineStarts[line-1] + column - 1
+	return pos >= 0 && pos < len(buf) && buf[pos] == '"'
+}
+
 func makeChunk(tableName string, columnNames []string, seeds mysql.Seeds) *mysql.Chunk {
 	return &mysql.Chunk{
 		TableName:   tableName,
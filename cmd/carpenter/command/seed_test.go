@@ -1,7 +1,317 @@
 package command
 
-import "testing"
+import (
+	"database/sql"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/dev-cloverlab/carpenter/dialect/mysql"
+	"github.com/dev-cloverlab/carpenter/dialect/sqlite"
+	"github.com/dev-cloverlab/carpenter/planner"
+)
 
 func TestCmdSeed(t *testing.T) {
 	// Write your code here
 }
+
+func TestMakeSeedChangesOrder(t *testing.T) {
+	openTestDB(t)
+	defer db.Close()
+	sqlDialect = sqlite.Dialect{}
+	schema = "main"
+	if err := execute([]string{
+		`create table "user" ("id" integer primary key, "name" text)`,
+		`create table "item" ("id" integer primary key, "user_id" integer references "user" ("id"))`,
+		`create table "category" ("id" integer primary key)`,
+		`insert into "user" values (1, 'a'), (2, 'b'), (3, 'c')`,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	dir, err := ioutil.TempDir("", "carpenter")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	files := map[string]string{
+		"user.csv":     "id,name\n5,e\n2,x\n4,d\n3,y\n",
+		"item.csv":     "id,user_id\n1,4\n2,5\n",
+		"category.csv": "id\n1\n",
+	}
+	for name, body := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(body), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	expected := []*planner.Change{
+		{TableName: "category", Kind: planner.KindData, Queries: []string{
+			"insert into \"category\"(\"id\")\nvalues\n(1)",
+		}},
+		{TableName: "user", Kind: planner.KindData, Queries: []string{
+			"delete from \"user\" where \"id\" in (\n1\n)",
+			"insert into \"user\"(\"id\",\"name\")\nvalues\n(2,'x'),\n(3,'y')\non conflict (\"id\") do update set \"name\"=excluded.\"name\"",
+			"insert into \"user\"(\"id\",\"name\")\nvalues\n(5,'e'),\n(4,'d')",
+		}},
+		{TableName: "item", Kind: planner.KindData, Queries: []string{
+			"insert into \"item\"(\"id\",\"user_id\")\nvalues\n(1,4),\n(2,5)",
+		}},
+	}
+	for i := 0; i < 30; i++ {
+		changes, errs := makeSeedChanges(dir, nil, "NULL")
+		if len(errs) > 0 {
+			t.Fatal(errs)
+		}
+		if !reflect.DeepEqual(changes, expected) {
+			t.Fatalf("err: unexpected changes returned in %d times.\nactual:\n%v\nexpected:\n%v\n", i+1, (&planner.Plan{Changes: changes}).Queries(), (&planner.Plan{Changes: expected}).Queries())
+		}
+	}
+}
+
+func TestExecuteSeedChangesIgnoreForeignKey(t *testing.T) {
+	openTestDB(t)
+	defer db.Close()
+	sqlDialect = sqlite.Dialect{}
+	if err := execute([]string{
+		"pragma foreign_keys = on",
+		`create table "user" ("id" integer primary key)`,
+		`create table "item" ("id" integer primary key, "user_id" integer references "user" ("id"))`,
+	}); err != nil {
+		t.Fatal(err)
+	}
+	changes := []*planner.Change{
+		{TableName: "item", Kind: planner.KindData, Queries: []string{`insert into "item" values (1, 1)`}},
+		{TableName: "user", Kind: planner.KindData, Queries: []string{`insert into "user" values (1)`}},
+	}
+	if err := executeSeedChanges(changes, false, false); err == nil {
+		t.Fatal("err: executeSeedChanges must fail by the foreign key")
+	}
+	if err := executeSeedChanges(changes, false, true); err != nil {
+		t.Fatal(err)
+	}
+	if cnt := countRows(t, `"item"`); cnt != 1 {
+		t.Errorf("err: unexpected count %d", cnt)
+	}
+	var enabled int
+	if err := db.QueryRow("pragma foreign_keys").Scan(&enabled); err != nil {
+		t.Fatal(err)
+	}
+	if enabled != 1 {
+		t.Error("err: foreign key check is not switched on again")
+	}
+}
+
+func TestMakeSeedChangesCompositeKey(t *testing.T) {
+	openTestDB(t)
+	defer db.Close()
+	sqlDialect = sqlite.Dialect{}
+	schema = "main"
+	if err := execute([]string{
+		`create table "user_item" ("user_id" integer not null, "item_id" integer not null, "count" integer not null, primary key ("user_id", "item_id"))`,
+		`insert into "user_item" values (1, 1, 1), (1, 2, 1), (2, 1, 1)`,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	dir, err := ioutil.TempDir("", "carpenter")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	if err := ioutil.WriteFile(filepath.Join(dir, "user_item.csv"), []byte("user_id,item_id,count\n1,1,1\n2,1,5\n2,2,1\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		"delete from \"user_item\" where (\"user_id\",\"item_id\") in (\n(1,2)\n)",
+		"insert into \"user_item\"(\"user_id\",\"item_id\",\"count\")\nvalues\n(2,1,5)\non conflict (\"user_id\",\"item_id\") do update set \"count\"=excluded.\"count\"",
+		"insert into \"user_item\"(\"user_id\",\"item_id\",\"count\")\nvalues\n(2,2,1)",
+	}
+	changes, errs := makeSeedChanges(dir, nil, "NULL")
+	if len(errs) > 0 {
+		t.Fatal(errs)
+	}
+	actual := (&planner.Plan{Changes: changes}).Queries()
+	if !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("err: unexpected queries returned.\nactual:\n%s\nexpected:\n%s\n", actual, expected)
+	}
+	if err := execute(actual); err != nil {
+		t.Fatal(err)
+	}
+	if changes, errs := makeSeedChanges(dir, nil, "NULL"); len(errs) > 0 || len(changes) > 0 {
+		t.Errorf("err: rows are not synchronized %v %v", changes, errs)
+	}
+}
+
+func TestGetSeedKeys(t *testing.T) {
+	dir, err := ioutil.TempDir("", "carpenter")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	keyFile := filepath.Join(dir, "keys.json")
+	if err := ioutil.WriteFile(keyFile, []byte(`{"item": ["code"], "user_item": ["user_id", "item_id"]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	keys, err := getSeedKeys([]string{"item=slug", "tag=name,lang"}, keyFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string][]string{
+		"item":      {"slug"},
+		"tag":       {"name", "lang"},
+		"user_item": {"user_id", "item_id"},
+	}
+	if !reflect.DeepEqual(keys, expected) {
+		t.Errorf("err: unexpected keys %v", keys)
+	}
+	for _, option := range []string{"item", "=code", "item="} {
+		if _, err := getSeedKeys([]string{option}, ""); err == nil {
+			t.Errorf("err: invalid key option `%s' is accepted", option)
+		}
+	}
+}
+
+func TestMakeSeedChangesKey(t *testing.T) {
+	openTestDB(t)
+	defer db.Close()
+	sqlDialect = sqlite.Dialect{}
+	schema = "main"
+	if err := execute([]string{
+		`create table "master" ("id" integer, "code" text not null unique, "name" text not null)`,
+		`create table "log" ("message" text)`,
+		`create table "tag" ("message" text)`,
+		`insert into "master" values (1, 'a', 'A'), (2, 'b', 'B')`,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	dir, err := ioutil.TempDir("", "carpenter")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	files := map[string]string{
+		"master.csv": "id,code,name\n1,a,A\n3,b,X\n",
+		"log.csv":    "message\nfoo\n",
+		"tag.csv":    "message\nfoo\n",
+	}
+	for name, body := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(body), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	_, errs := makeSeedChanges(dir, map[string][]string{"master": {"code"}}, "NULL")
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "Tables log, tag have neither") {
+		t.Fatalf("err: unexpected errors %v", errs)
+	}
+
+	keys := map[string][]string{"master": {"code"}, "log": {"message"}, "tag": {"message"}}
+	_, errs = makeSeedChanges(dir, keys, "NULL")
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "tables log, tag are neither") {
+		t.Fatalf("err: unexpected errors %v", errs)
+	}
+	if err := execute([]string{
+		`create unique index "uniq_log_message" on "log" ("message")`,
+		`create unique index "uniq_tag_message" on "tag" ("message")`,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	changes, errs := makeSeedChanges(dir, keys, "NULL")
+	if len(errs) > 0 {
+		t.Fatal(errs)
+	}
+	for _, change := range changes {
+		if change.TableName != "master" {
+			continue
+		}
+		expected := []string{
+			"insert into \"master\"(\"id\",\"code\",\"name\")\nvalues\n(3,'b','X')\non conflict (\"code\") do update set \"id\"=excluded.\"id\",\"name\"=excluded.\"name\"",
+		}
+		if !reflect.DeepEqual(change.Queries, expected) {
+			t.Errorf("err: unexpected queries returned.\nactual:\n%s\nexpected:\n%s\n", change.Queries, expected)
+		}
+		if err := execute(change.Queries); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestParseCSV(t *testing.T) {
+	dir, err := ioutil.TempDir("", "carpenter")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	columns := mysql.Columns{
+		{ColumnName: "id", DataType: "bigint", ColumnType: "bigint(20)"},
+		{ColumnName: "name", DataType: "varchar", ColumnType: "varchar(32)"},
+		{ColumnName: "price", DataType: "decimal", ColumnType: "decimal(10,2)"},
+		{ColumnName: "created_at", DataType: "datetime", ColumnType: "datetime"},
+	}
+
+	path := filepath.Join(dir, "item.csv")
+	body := "id,name,price,created_at\n" +
+		"9007199254740993,null,1.50,2017-01-02 03:04:05\n" +
+		"2,1e3,\\N,\\N\n" +
+		"3,\"\\N\",\\N,\\N\n"
+	if err := ioutil.WriteFile(path, []byte(body), 0644); err != nil {
+		t.Fatal(err)
+	}
+	names, seeds, err := parseCSV(path, columns, `\N`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := []string{"id", "name", "price", "created_at"}; !reflect.DeepEqual(names, expected) {
+		t.Errorf("err: unexpected column names %v", names)
+	}
+	expected := mysql.Seeds{
+		{ColumnData: []interface{}{int64(9007199254740993), "null", mysql.Decimal("1.5"), "2017-01-02 03:04:05"}},
+		{ColumnData: []interface{}{int64(2), "1e3", nil, nil}},
+		{ColumnData: []interface{}{int64(3), `\N`, nil, nil}},
+	}
+	if !reflect.DeepEqual(seeds, expected) {
+		t.Errorf("err: unexpected seeds.\nexpected %#v\nbut actual %#v", expected, seeds)
+	}
+
+	tests := []struct {
+		body     string
+		expected string
+	}{
+		{"id,name\n1,a\n\"2\nx\",b\n", "item.csv:3: column `id': invalid bigint value `2\nx'"},
+		{"id,name,price\n1,a,1\n2,\"b\nc\",abc\n", "item.csv:4: column `price': invalid decimal value `abc'"},
+		{"id,created_at\n1,2017-02-30\n", "item.csv:2: column `created_at': invalid datetime value `2017-02-30'"},
+		{"id,unknown\n", "item.csv:1: column `unknown' is not found in the table"},
+	}
+	for _, test := range tests {
+		if err := ioutil.WriteFile(path, []byte(test.body), 0644); err != nil {
+			t.Fatal(err)
+		}
+		_, _, err := parseCSV(path, columns, "NULL")
+		if err == nil || !strings.HasSuffix(err.Error(), test.expected) {
+			t.Errorf("err: unexpected error %v\nexpected %s", err, test.expected)
+		}
+	}
+	// the fields of the generated columns are skipped
+	columns = append(columns, &mysql.Column{ColumnName: "total", DataType: "decimal", ColumnType: "decimal(10,2)", GenerationExpression: "`price` * 2", Extra: mysql.JsonNullString{NullString: sql.NullString{String: "VIRTUAL GENERATED", Valid: true}}})
+	if err := ioutil.WriteFile(path, []byte("id,total,name\n1,abc,a\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	names, seeds, err = parseCSV(path, columns, "NULL")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := []string{"id", "name"}; !reflect.DeepEqual(names, expected) {
+		t.Errorf("err: unexpected column names %v", names)
+	}
+	if expected := (mysql.Seeds{{ColumnData: []interface{}{int64(1), "a"}}}); !reflect.DeepEqual(seeds, expected) {
+		t.Errorf("err: unexpected seeds.\nexpected %#v\nbut actual %#v", expected, seeds)
+	}
+}
@@ -1,6 +1,8 @@
 package command
 
 import (
+	"context"
+	"database/sql"
 	"fmt"
 	"io/ioutil"
 	"os"
@@ -15,10 +17,77 @@ func getErrorMessages(errs []error) []string {
 	return msg
 }
 
+type execer interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+}
+
 func execute(queries []string) error {
+	return executeWith(db, queries)
+}
+
+// executeInTransaction executes the queries in a transaction and rolls back all of them on error.
+// When ignoreForeignKey is true, the foreign key check is switched off on the connection before the transaction
+// and switched on after it, because it is a session variable and the pragma of SQLite has no effect in a transaction.
+func executeInTransaction(queries []string, ignoreForeignKey bool) error {
+	if len(queries) <= 0 {
+		return nil
+	}
+	if dryrun {
+		if ignoreForeignKey {
+			queries = append(append([]string{sqlDialect.ForeignKeyCheck(false)}, queries...), sqlDialect.ForeignKeyCheck(true))
+		}
+		return executeWith(db, queries)
+	}
+	ctx := context.Background()
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("err: db.Conn failed for reason %s", err)
+	}
+	defer conn.Close()
+	if ignoreForeignKey {
+		if err := executeWith(connExecer{conn}, []string{sqlDialect.ForeignKeyCheck(false)}); err != nil {
+			return err
+		}
+	}
+	err = executeInTx(conn, queries)
+	if ignoreForeignKey {
+		if ferr := executeWith(connExecer{conn}, []string{sqlDialect.ForeignKeyCheck(true)}); ferr != nil && err == nil {
+			err = ferr
+		}
+	}
+	return err
+}
+
+func executeInTx(conn *sql.Conn, queries []string) error {
+	tx, err := conn.BeginTx(context.Background(), nil)
+	if err != nil {
+		return fmt.Errorf("err: conn.BeginTx failed for reason %s", err)
+	}
+	if err := executeWith(tx, queries); err != nil {
+		if rerr := tx.Rollback(); rerr != nil {
+			return fmt.Errorf("%s and tx.Rollback failed for reason %s", err, rerr)
+		}
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("err: tx.Commit failed for reason %s", err)
+	}
+	return nil
+}
+
+// connExecer executes the queries on the dedicated connection.
+type connExecer struct {
+	conn *sql.Conn
+}
+
+func (e connExecer) Exec(query string, args ...interface{}) (sql.Result, error) {
+	return e.conn.ExecContext(context.Background(), query, args...)
+}
+
+func executeWith(e execer, queries []string) error {
 	for _, query := range queries {
 		if !dryrun {
-			if _, err := db.Exec(query); err != nil {
+			if _, err := e.Exec(query); err != nil {
 				return fmt.Errorf("err: db.Exec `%s' failed for reason %s", query, err)
 			}
 		}
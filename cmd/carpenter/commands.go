@@ -9,6 +9,12 @@ import (
 )
 
 var GlobalFlags = []cli.Flag{
+	cli.StringFlag{
+		Name:   "driver",
+		Usage:  "database driver name (mysql|postgres|sqlite3)",
+		Hidden: false,
+		Value:  "mysql",
+	},
 	cli.BoolFlag{
 		Name:   "verbose, vv",
 		Hidden: false,
@@ -26,7 +32,7 @@ var GlobalFlags = []cli.Flag{
 	},
 	cli.StringFlag{
 		Name:   "data-source, d",
-		Usage:  "data source name like '[username[:password]@][tcp[(address:port)]]' (required)",
+		Usage:  "data source name like '[username[:password]@][tcp[(address:port)]]' or 'postgres://[username[:password]@]address[:port][?param=value]' or the path of SQLite database file (required)",
 		Hidden: false,
 	},
 	cli.IntFlag{
@@ -80,7 +86,115 @@ var Commands = []cli.Command{
 			},
 			cli.BoolFlag{
 				Name:   "with-drop",
-				Usage:  "drop table when if JSON file does not exist",
+				Usage:  "drop table when if JSON file does not exist, and drop partition when it is not in JSON file",
+				Hidden: false,
+			},
+			cli.StringFlag{
+				Name:   "journal, j",
+				Usage:  "path to the journal file which records the progress to resume a failed build (default carpenter_build_<schema>.journal)",
+				Hidden: false,
+			},
+			cli.BoolFlag{
+				Name:   "detect-rename",
+				Usage:  "rename column, index and table which has same definition instead of drop and add (default off)",
+				Hidden: false,
+			},
+		},
+	},
+	{
+		Name:   "plan",
+		Usage:  "Write the SQLs to build tables and import CSV as SQL files",
+		Before: command.Before,
+		Action: command.CmdPlan,
+		Flags: []cli.Flag{
+			cli.StringFlag{
+				Name:   "dir, d",
+				Usage:  "path to JSON file directory",
+				Hidden: false,
+			},
+			cli.StringFlag{
+				Name:   "data-dir, c",
+				Usage:  "path to CSV file directory",
+				Hidden: false,
+			},
+			cli.StringSliceFlag{
+				Name:   "key, k",
+				Usage:  "key columns to compare rows like 'table=col1,col2' (default primary key)",
+				Value:  &cli.StringSlice{},
+				Hidden: false,
+			},
+			cli.StringFlag{
+				Name:   "key-file",
+				Usage:  "path to JSON file which maps table names to key columns like '{\"table\": [\"col1\", \"col2\"]}'",
+				Hidden: false,
+			},
+			cli.StringFlag{
+				Name:   "null",
+				Usage:  "field which represents NULL in CSV files",
+				Value:  "NULL",
+				Hidden: false,
+			},
+			cli.StringFlag{
+				Name:   "out, o",
+				Usage:  "path to output directory (default execution dir)",
+				Hidden: false,
+			},
+			cli.BoolFlag{
+				Name:   "separate, s",
+				Usage:  "output for each table (default off)",
+				Hidden: false,
+			},
+			cli.BoolFlag{
+				Name:   "with-drop",
+				Usage:  "drop table when if JSON file does not exist, and drop partition when it is not in JSON file",
+				Hidden: false,
+			},
+			cli.BoolFlag{
+				Name:   "detect-rename",
+				Usage:  "rename column, index and table which has same definition instead of drop and add (default off)",
+				Hidden: false,
+			},
+		},
+	},
+	{
+		Name:   "apply",
+		Usage:  "Execute the SQL files written by plan command",
+		Before: command.Before,
+		Action: command.CmdApply,
+		Flags: []cli.Flag{
+			cli.StringFlag{
+				Name:   "dir, d",
+				Usage:  "path to the plan file or directory (required)",
+				Hidden: false,
+			},
+			cli.BoolFlag{
+				Name:   "ignore-foreign-key, i",
+				Usage:  "ignore foreign key check while importing data",
+				Hidden: false,
+			},
+		},
+	},
+	{
+		Name:   "partition",
+		Usage:  "Add daily partitions in advance and drop expired ones",
+		Before: command.Before,
+		Action: command.CmdPartition,
+		Flags: []cli.Flag{
+			cli.StringSliceFlag{
+				Name:   "retention, r",
+				Usage:  "days to keep the partitions of table like 'table=30'",
+				Value:  &cli.StringSlice{},
+				Hidden: false,
+			},
+			cli.StringFlag{
+				Name:   "config, c",
+				Usage:  "path to JSON file which maps table names to policies like '{\"table\": {\"Retention\": 30, \"Ahead\": 7}}'",
+				Hidden: false,
+			},
+			cli.IntFlag{
+				Name:   "ahead, a",
+				Usage:  "days after today to add partitions in advance",
+				Value:  7,
 				Hidden: false,
 			},
 		},
@@ -96,6 +210,28 @@ var Commands = []cli.Command{
 				Usage:  "path to CSV file directory (required)",
 				Hidden: false,
 			},
+			cli.StringSliceFlag{
+				Name:   "key, k",
+				Usage:  "key columns to compare rows like 'table=col1,col2' (default primary key)",
+				Value:  &cli.StringSlice{},
+				Hidden: false,
+			},
+			cli.StringFlag{
+				Name:   "key-file",
+				Usage:  "path to JSON file which maps table names to key columns like '{\"table\": [\"col1\", \"col2\"]}'",
+				Hidden: false,
+			},
+			cli.StringFlag{
+				Name:   "null",
+				Usage:  "field which represents NULL in CSV files",
+				Value:  "NULL",
+				Hidden: false,
+			},
+			cli.BoolFlag{
+				Name:   "single-transaction",
+				Usage:  "import all tables in a single transaction instead of a transaction for each table (default off)",
+				Hidden: false,
+			},
 			cli.BoolFlag{
 				Name:   "ignore-foreign-key, i",
 				Usage:  "ignore foreign key check",
@@ -119,6 +255,24 @@ var Commands = []cli.Command{
 				Usage:  "regular expression for exporting table (default all)",
 				Hidden: false,
 			},
+			cli.IntFlag{
+				Name:   "page-size, p",
+				Usage:  "number of rows read by a query in the order of primary key (default 0, all rows by a query)",
+				Hidden: false,
+				Value:  0,
+			},
+			cli.StringFlag{
+				Name:   "null",
+				Usage:  "field which represents NULL in CSV files",
+				Value:  "NULL",
+				Hidden: false,
+			},
+			cli.StringFlag{
+				Name:   "binary-encoding",
+				Usage:  "encoding of binary columns (hex|base64)",
+				Value:  "hex",
+				Hidden: false,
+			},
 		},
 	},
 }
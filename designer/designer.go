@@ -3,9 +3,46 @@ package designer
 import (
 	"database/sql"
 
+	"github.com/dev-cloverlab/carpenter/dialect"
 	"github.com/dev-cloverlab/carpenter/dialect/mysql"
 )
 
-func Export(db *sql.DB, schema string, tableNames ...string) (mysql.Tables, error) {
-	return mysql.GetTables(db, schema, tableNames...)
+func Export(db *sql.DB, d dialect.Dialect, schema string, tableNames ...string) (mysql.Tables, error) {
+	return d.GetTables(db, schema, tableNames...)
+}
+
+// ExportViews returns the views of the schema, or nothing for the dialect which does not manage views.
+func ExportViews(db *sql.DB, d dialect.Dialect, schema string) (mysql.Views, error) {
+	vd, ok := d.(dialect.ViewDialect)
+	if !ok {
+		return nil, nil
+	}
+	return vd.GetViews(db, schema)
+}
+
+// ExportRoutines returns the triggers and the stored routines of the schema,
+// or nothing for the dialect which does not manage them.
+func ExportRoutines(db *sql.DB, d dialect.Dialect, schema string) (mysql.Triggers, mysql.Routines, error) {
+	rd, ok := d.(dialect.RoutineDialect)
+	if !ok {
+		return nil, nil, nil
+	}
+	triggers, err := rd.GetTriggers(db, schema)
+	if err != nil {
+		return nil, nil, err
+	}
+	routines, err := rd.GetRoutines(db, schema)
+	if err != nil {
+		return nil, nil, err
+	}
+	return triggers, routines, nil
+}
+
+// ExportEvents returns the scheduled events of the schema, or nothing for the dialect which does not manage events.
+func ExportEvents(db *sql.DB, d dialect.Dialect, schema string) (mysql.Events, error) {
+	ed, ok := d.(dialect.EventDialect)
+	if !ok {
+		return nil, nil
+	}
+	return ed.GetEvents(db, schema)
 }
@@ -62,7 +62,7 @@ func TestExport(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	buf, err := Export(db, schema, "design_test")
+	buf, err := Export(db, mysql.Dialect{}, schema, "design_test")
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -0,0 +1,76 @@
+package dialect
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/dev-cloverlab/carpenter/dialect/mysql"
+	"github.com/dev-cloverlab/carpenter/dialect/postgres"
+	"github.com/dev-cloverlab/carpenter/dialect/sqlite"
+)
+
+// Dialect is a set of database specific operations.
+// The structs of mysql package are used as the common representation of tables and data
+// so that JSON and CSV files can be shared between databases.
+type Dialect interface {
+	DriverName() string
+	DataSourceName(dataSource, schema string) string
+	Quote(name string) string
+	QuoteString(value string) string
+	ForeignKeyCheck(turnOn bool) string
+	Placeholder(n int) string
+
+	GetTables(db *sql.DB, schema string, tableNames ...string) (mysql.Tables, error)
+	GetIndices(db *sql.DB, table string) (mysql.Indices, error)
+
+	ToCreateSQL(table *mysql.Table) []string
+	ToDropSQL(table *mysql.Table) string
+	ToRenameSQL(table *mysql.Table, name string) string
+	ToAlterSQL(table *mysql.Table, alter *mysql.Alter) ([]string, error)
+
+	// ToDeleteAllSQL returns the statement which deletes all rows and can be rolled back in a transaction.
+	ToDeleteAllSQL(cnk *mysql.Chunk) string
+	ToInsertSQL(cnk *mysql.Chunk) []string
+	ToReplaceSQL(cnk *mysql.Chunk, keyNames []string) []string
+	ToDeleteSQL(cnk *mysql.Chunk, colIdxs []int) []string
+}
+
+// ViewDialect is implemented by the dialects which manage views.
+type ViewDialect interface {
+	GetViews(db *sql.DB, schema string) (mysql.Views, error)
+	ToCreateViewSQL(view *mysql.View) string
+	ToDropViewSQL(view *mysql.View) string
+}
+
+// RoutineDialect is implemented by the dialects which manage triggers and stored routines.
+type RoutineDialect interface {
+	GetTriggers(db *sql.DB, schema string) (mysql.Triggers, error)
+	GetRoutines(db *sql.DB, schema string) (mysql.Routines, error)
+	ToCreateTriggerSQL(trigger *mysql.Trigger) string
+	ToDropTriggerSQL(trigger *mysql.Trigger) string
+	ToCreateRoutineSQL(routine *mysql.Routine) string
+	ToDropRoutineSQL(routine *mysql.Routine) string
+}
+
+// EventDialect is implemented by the dialects which manage scheduled events.
+type EventDialect interface {
+	GetEvents(db *sql.DB, schema string) (mysql.Events, error)
+	ToCreateEventSQL(event *mysql.Event) string
+	ToAlterEventSQL(old, new *mysql.Event) string
+	ToDropEventSQL(event *mysql.Event) string
+}
+
+var dialects = map[string]Dialect{
+	"mysql":    mysql.Dialect{},
+	"postgres": postgres.Dialect{},
+	"sqlite3":  sqlite.Dialect{},
+}
+
+// Get returns the dialect for the specified driver name.
+func Get(driverName string) (Dialect, error) {
+	d, ok := dialects[driverName]
+	if !ok {
+		return nil, fmt.Errorf("err: Unsupported driver `%s'", driverName)
+	}
+	return d, nil
+}
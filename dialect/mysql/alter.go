@@ -0,0 +1,111 @@
+package mysql
+
+type (
+	// Alter is a set of differences between the old and new definition of a table.
+	// It is detected by builder and each dialect renders it to the alter statements.
+	Alter struct {
+		ConvertCharset       bool
+		ModifyCharsetColumns Columns
+		DropForeignKeys      ForeignKeys
+		DropCheckConstraints CheckConstraints
+		DropIndices          Indices
+		DropColumns          Columns
+		ChangeColumns        []*ChangeColumn
+		RenameIndices        []*RenameIndex
+		AddColumns           Columns
+		AddIndices           []*AddIndex
+		// AlterIndices is the indices whose visibility is only changed
+		AlterIndices  Indices
+		ModifyColumns Columns
+		// ModifyComments is the existing columns whose comment is changed,
+		// for the dialects which can not modify the comment with the column
+		ModifyComments      Columns
+		AddForeignKeys      ForeignKeys
+		AddCheckConstraints CheckConstraints
+		// AlterCheckConstraints is the constraints whose enforcement is changed
+		AlterCheckConstraints CheckConstraints
+		// Partitions repartitions the table when the partitioning method is changed
+		Partitions         Partitions
+		RemovePartitioning bool
+		// DropPartitions, ReorganizePartitions and AddPartitions change the partitions of RANGE or LIST in place
+		DropPartitions       Partitions
+		ReorganizePartitions []*ReorganizePartition
+		AddPartitions        Partitions
+	}
+	ChangeColumn struct {
+		OldName string
+		Column  *Column
+	}
+	RenameIndex struct {
+		Index Index
+		Name  string
+	}
+	// ReorganizePartition replaces the adjacent Partitions with Into which cover the same range or values.
+	ReorganizePartition struct {
+		Partitions Partitions
+		Into       Partitions
+	}
+	// AddIndex has Replace when the index of the same name already exists.
+	AddIndex struct {
+		Replace Indices
+		Indices Indices
+	}
+)
+
+// ToAlterSQLs returns an independent statement for dropping the foreign keys and the CHECK constraints and the statement for others,
+// because MySQL can not drop and add a constraint of the same name in a single statement.
+// Adding, dropping and reorganizing partitions follow as independent statements, because they can not be combined with other changes.
+func (m *Table) ToAlterSQLs(alter *Alter) []string {
+	queries := []string{}
+	if q := m.ToAlterSQL(append(alter.DropForeignKeys.ToDropSQL(), alter.DropCheckConstraints.ToDropSQL()...), ""); len(q) > 0 {
+		queries = append(queries, q)
+	}
+	sqls := []string{}
+	if alter.ConvertCharset {
+		sqls = append(sqls, m.ToConvertCharsetSQL())
+	}
+	for _, column := range alter.ModifyCharsetColumns {
+		sqls = append(sqls, column.ToModifyCharsetSQL())
+	}
+	for _, index := range alter.DropIndices {
+		sqls = append(sqls, Indices{index}.ToDropSQL()...)
+	}
+	sqls = append(sqls, alter.DropColumns.ToDropSQL()...)
+	for _, change := range alter.ChangeColumns {
+		sqls = append(sqls, change.Column.ToChangeSQL(change.OldName))
+	}
+	for _, rename := range alter.RenameIndices {
+		sqls = append(sqls, rename.Index.ToRenameSQL(rename.Name))
+	}
+	sqls = append(sqls, alter.AddColumns.ToAddSQL(m.Columns)...)
+	for _, add := range alter.AddIndices {
+		sqls = append(sqls, add.Replace.ToDropSQL()...)
+		sqls = append(sqls, add.Indices.ToAddSQL()...)
+	}
+	for _, index := range alter.AlterIndices {
+		sqls = append(sqls, index.ToAlterVisibilitySQL())
+	}
+	for _, column := range alter.ModifyColumns {
+		sqls = append(sqls, column.ToModifySQL())
+	}
+	sqls = append(sqls, alter.AddForeignKeys.ToAddSQL()...)
+	sqls = append(sqls, alter.AddCheckConstraints.ToAddSQL()...)
+	sqls = append(sqls, alter.AlterCheckConstraints.ToAlterSQL()...)
+	partitionSQL := alter.Partitions.ToSQL()
+	if alter.RemovePartitioning {
+		partitionSQL = "remove partitioning"
+	}
+	if q := m.ToAlterSQL(sqls, partitionSQL); len(q) > 0 {
+		queries = append(queries, q)
+	}
+	if len(alter.DropPartitions) > 0 {
+		queries = append(queries, m.ToAlterSQL([]string{alter.DropPartitions.ToDropSQL()}, ""))
+	}
+	for _, reorganize := range alter.ReorganizePartitions {
+		queries = append(queries, m.ToAlterSQL([]string{reorganize.ToSQL()}, ""))
+	}
+	if len(alter.AddPartitions) > 0 {
+		queries = append(queries, m.ToAlterSQL([]string{alter.AddPartitions.ToAddSQL()}, ""))
+	}
+	return queries
+}
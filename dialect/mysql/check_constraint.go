@@ -0,0 +1,166 @@
+package mysql
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+type (
+	// CheckConstraint is a CHECK constraint of MySQL 8.0.16 or later and MariaDB 10.2 or later.
+	// Enforced is "NO" for the constraint which is defined as not enforced,
+	// and empty for the servers which do not have the enforcement like MariaDB.
+	CheckConstraint struct {
+		ConstraintName string
+		TableSchema    string
+		TableName      string
+		CheckClause    string
+		Enforced       string
+	}
+	CheckConstraints []*CheckConstraint
+)
+
+func (m *CheckConstraint) IsEnforced() bool {
+	return m.Enforced != "NO"
+}
+
+func (m *CheckConstraint) ToSQL() string {
+	token := []string{"constraint", Quote(m.ConstraintName), fmt.Sprintf("check (%s)", m.CheckClause)}
+	if !m.IsEnforced() {
+		token = append(token, "not enforced")
+	}
+	return strings.Join(token, " ")
+}
+
+func (m *CheckConstraint) ToAddSQL() string {
+	return fmt.Sprintf("add %s", m.ToSQL())
+}
+
+// ToDropSQL returns `drop constraint' instead of `drop check' of MySQL, which MariaDB does not have.
+func (m *CheckConstraint) ToDropSQL() string {
+	return fmt.Sprintf("drop constraint %s", Quote(m.ConstraintName))
+}
+
+func (m *CheckConstraint) ToAlterSQL() string {
+	if m.IsEnforced() {
+		return fmt.Sprintf("alter check %s enforced", Quote(m.ConstraintName))
+	}
+	return fmt.Sprintf("alter check %s not enforced", Quote(m.ConstraintName))
+}
+
+// EqualClause reports whether both constraints have the same name and clause regardless of the enforcement.
+func (m *CheckConstraint) EqualClause(c *CheckConstraint) bool {
+	return m.ConstraintName == c.ConstraintName && m.CheckClause == c.CheckClause
+}
+
+func (m CheckConstraints) ToSQL() []string {
+	sqls := make([]string, 0, len(m))
+	for _, c := range m.getSortedCheckConstraints() {
+		sqls = append(sqls, c.ToSQL())
+	}
+	return sqls
+}
+
+func (m CheckConstraints) ToAddSQL() []string {
+	sqls := make([]string, 0, len(m))
+	for _, c := range m.getSortedCheckConstraints() {
+		sqls = append(sqls, c.ToAddSQL())
+	}
+	return sqls
+}
+
+func (m CheckConstraints) ToDropSQL() []string {
+	sqls := make([]string, 0, len(m))
+	for _, c := range m.getSortedCheckConstraints() {
+		sqls = append(sqls, c.ToDropSQL())
+	}
+	return sqls
+}
+
+func (m CheckConstraints) ToAlterSQL() []string {
+	sqls := make([]string, 0, len(m))
+	for _, c := range m.getSortedCheckConstraints() {
+		sqls = append(sqls, c.ToAlterSQL())
+	}
+	return sqls
+}
+
+func (m CheckConstraints) GroupByConstraintName() map[string]*CheckConstraint {
+	nameMap := make(map[string]*CheckConstraint, len(m))
+	for _, c := range m {
+		nameMap[c.ConstraintName] = c
+	}
+	return nameMap
+}
+
+func (m CheckConstraints) GetSortedConstraintNames() []string {
+	names := make([]string, 0, len(m))
+	for _, c := range m {
+		names = append(names, c.ConstraintName)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (m CheckConstraints) getSortedCheckConstraints() CheckConstraints {
+	nameMap := m.GroupByConstraintName()
+	sorted := make(CheckConstraints, 0, len(m))
+	for _, name := range m.GetSortedConstraintNames() {
+		sorted = append(sorted, nameMap[name])
+	}
+	return sorted
+}
+
+// GetCheckConstraints returns the CHECK constraints of the tables in the schema.
+// It returns no constraints for the servers which do not have information_schema.CHECK_CONSTRAINTS.
+func GetCheckConstraints(db *sql.DB, schema string) (CheckConstraints, error) {
+	supported, err := hasInformationSchemaColumn(db, "CHECK_CONSTRAINTS", "CHECK_CLAUSE")
+	if err != nil || !supported {
+		return CheckConstraints{}, err
+	}
+	// ENFORCED is only of MySQL
+	enforced := "''"
+	if ok, err := hasInformationSchemaColumn(db, "TABLE_CONSTRAINTS", "ENFORCED"); err != nil {
+		return nil, err
+	} else if ok {
+		enforced = "tc.ENFORCED"
+	}
+	// the names of the constraints of MariaDB are unique in the table instead of the schema
+	on := "cc.CONSTRAINT_SCHEMA=tc.CONSTRAINT_SCHEMA and cc.CONSTRAINT_NAME=tc.CONSTRAINT_NAME"
+	if ok, err := hasInformationSchemaColumn(db, "CHECK_CONSTRAINTS", "TABLE_NAME"); err != nil {
+		return nil, err
+	} else if ok {
+		on += " and cc.TABLE_NAME=tc.TABLE_NAME"
+	}
+
+	query := fmt.Sprintf(`select tc.CONSTRAINT_NAME,tc.TABLE_SCHEMA,tc.TABLE_NAME,cc.CHECK_CLAUSE,%s
+	from information_schema.TABLE_CONSTRAINTS tc join information_schema.CHECK_CONSTRAINTS cc on %s
+	where tc.TABLE_SCHEMA=%s and tc.CONSTRAINT_TYPE='CHECK'`, enforced, on, QuoteString(schema))
+	rows, err := db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("err: db.Query `%s' failed for reason %s", query, err)
+	}
+	defer rows.Close()
+
+	checks := CheckConstraints{}
+	for rows.Next() {
+		c := &CheckConstraint{}
+		if err := rows.Scan(&c.ConstraintName, &c.TableSchema, &c.TableName, &c.CheckClause, &c.Enforced); err != nil {
+			return nil, err
+		}
+		checks = append(checks, c)
+	}
+	return checks, rows.Err()
+}
+
+// hasInformationSchemaColumn reports whether the table of information_schema has the column,
+// which depends on the version of the server.
+func hasInformationSchemaColumn(db *sql.DB, table, column string) (bool, error) {
+	query := fmt.Sprintf(`select count(*) from information_schema.COLUMNS where TABLE_SCHEMA='information_schema' and TABLE_NAME=%s and COLUMN_NAME=%s`, QuoteString(table), QuoteString(column))
+	var cnt int
+	if err := db.QueryRow(query).Scan(&cnt); err != nil {
+		return false, fmt.Errorf("err: db.QueryRow `%s' failed for reason %s", query, err)
+	}
+	return cnt > 0, nil
+}
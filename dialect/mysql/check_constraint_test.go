@@ -0,0 +1,47 @@
+package mysql
+
+import "testing"
+
+func TestCheckConstraintToSQL(t *testing.T) {
+	table := &Table{
+		TableName:      "item",
+		Engine:         "InnoDB",
+		TableCollation: "utf8mb4_general_ci",
+		Columns: Columns{
+			{ColumnName: "price", ColumnType: "int(11)", Nullable: "NO"},
+			{ColumnName: "discount", ColumnType: "int(11)", Nullable: "NO"},
+		},
+		CheckConstraints: CheckConstraints{
+			{ConstraintName: "item_price", CheckClause: "(`price` > 0)", Enforced: "YES"},
+			{ConstraintName: "item_discount", CheckClause: "(`discount` <= `price`)", Enforced: "NO"},
+		},
+	}
+	expected := "create table if not exists `item` (\n" +
+		"	`price` int(11) not null,\n" +
+		"	`discount` int(11) not null,\n" +
+		"	constraint `item_discount` check ((`discount` <= `price`)) not enforced,\n" +
+		"	constraint `item_price` check ((`price` > 0))\n" +
+		") engine=InnoDB default charset=utf8mb4 "
+	if actual := table.ToCreateSQL(); actual != expected {
+		t.Errorf("err: unexpected SQL.\nexpected %s\nbut actual %s", expected, actual)
+	}
+
+	alter := &Alter{
+		DropCheckConstraints:  CheckConstraints{table.CheckConstraints[0]},
+		AddCheckConstraints:   CheckConstraints{{ConstraintName: "item_price", CheckClause: "(`price` >= 0)"}},
+		AlterCheckConstraints: CheckConstraints{{ConstraintName: "item_discount", CheckClause: "(`discount` <= `price`)", Enforced: "YES"}},
+	}
+	expectedAlter := []string{
+		"alter table `item` drop constraint `item_price`\n\t",
+		"alter table `item` add constraint `item_price` check ((`price` >= 0)),\n\talter check `item_discount` enforced\n\t",
+	}
+	actual := table.ToAlterSQLs(alter)
+	if len(actual) != len(expectedAlter) {
+		t.Fatalf("err: unexpected SQLs %q", actual)
+	}
+	for i := range actual {
+		if actual[i] != expectedAlter[i] {
+			t.Errorf("err: unexpected SQL.\nexpected %q\nbut actual %q", expectedAlter[i], actual[i])
+		}
+	}
+}
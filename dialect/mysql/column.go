@@ -30,6 +30,8 @@ type (
 		Extra                  JsonNullString
 		Privileges             string
 		ColumnComment          string
+		GenerationExpression   string
+		PreviousNames          []string `json:",omitempty"`
 	}
 	Columns []*Column
 )
@@ -66,6 +68,25 @@ func (m *Column) HasComment() bool {
 	return m.ColumnComment != ""
 }
 
+// GenerationType returns "virtual" or "stored" for the generated column, or the empty string for the others.
+// The expression of the default value of MySQL 8.0 is also in GenerationExpression, whose Extra is DEFAULT_GENERATED.
+func (m *Column) GenerationType() string {
+	extra := strings.ToUpper(m.Extra.String)
+	switch {
+	case m.GenerationExpression == "":
+		return ""
+	case strings.Contains(extra, "VIRTUAL GENERATED"):
+		return "virtual"
+	case strings.Contains(extra, "STORED GENERATED"), strings.Contains(extra, "PERSISTENT GENERATED"):
+		return "stored"
+	}
+	return ""
+}
+
+func (m *Column) IsGenerated() bool {
+	return m.GenerationType() != ""
+}
+
 func (m *Column) HasDefaultValueNull() bool {
 	if r, err := regexp.MatchString(`(?i)null`, m.ColumnDefault.String); !r || err != nil {
 		return false
@@ -107,6 +128,17 @@ func (m *Column) CompareCollation(col *Column) bool {
 
 func (m *Column) ToSQL() string {
 	token := []string{Quote(m.ColumnName), m.ColumnType}
+	if m.IsGenerated() {
+		// generated columns have neither the default value nor the extra
+		token = append(token, fmt.Sprintf("as (%s)", m.GenerationExpression), m.GenerationType())
+		if !m.IsNullable() {
+			token = append(token, "not null")
+		}
+		if m.HasComment() {
+			token = append(token, "comment", QuoteString(m.ColumnComment))
+		}
+		return strings.Join(token, " ")
+	}
 	if !m.IsNullable() {
 		token = append(token, "not null")
 	}
@@ -130,6 +162,26 @@ func (m *Column) ToDropSQL() string {
 	return fmt.Sprintf("drop %s", Quote(m.ColumnName))
 }
 
+func (m *Column) ToChangeSQL(oldName string) string {
+	return fmt.Sprintf("change %s %s", Quote(oldName), m.ToSQL())
+}
+
+// EqualDefinition reports whether both columns have the same definition except for the name.
+func (m *Column) EqualDefinition(col *Column) bool {
+	a, b := *m, *col
+	a.ColumnName, b.ColumnName = "", ""
+	return a.ToSQL() == b.ToSQL() && a.CompareCharacterSet(&b) && a.CompareCollation(&b)
+}
+
+func (m *Column) HasPreviousName(name string) bool {
+	for _, n := range m.PreviousNames {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
 func (m *Column) ToModifySQL() string {
 	return fmt.Sprintf("modify %s", m.ToSQL())
 }
@@ -228,8 +280,9 @@ func GetColumns(db *sql.DB, schema string) ([]*Column, error) {
 		"EXTRA",
 		"PRIVILEGES",
 		"COLUMN_COMMENT",
+		"GENERATION_EXPRESSION",
 	}
-	query := fmt.Sprintf(`select %s from information_schema.columns where TABLE_SCHEMA="%s"`, strings.Join(selectCols, ","), schema)
+	query := fmt.Sprintf(`select %s from information_schema.columns where TABLE_SCHEMA=%s`, strings.Join(selectCols, ","), QuoteString(schema))
 	rows, err := db.Query(query)
 	if err != nil {
 		return nil, fmt.Errorf("err: db.Query failed `%s' for reason %s", query, err)
@@ -239,6 +292,8 @@ func GetColumns(db *sql.DB, schema string) ([]*Column, error) {
 	columns := []*Column{}
 	for rows.Next() {
 		column := &Column{}
+		// the expression is NULL for the columns which are not generated on MariaDB
+		var generationExpression sql.NullString
 		if err := rows.Scan(
 			&column.TableCatalog,
 			&column.TableSchema,
@@ -259,9 +314,11 @@ func GetColumns(db *sql.DB, schema string) ([]*Column, error) {
 			&column.Extra,
 			&column.Privileges,
 			&column.ColumnComment,
+			&generationExpression,
 		); err != nil {
 			return nil, err
 		}
+		column.GenerationExpression = generationExpression.String
 		columns = append(columns, column)
 	}
 	return columns, nil
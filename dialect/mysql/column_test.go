@@ -0,0 +1,59 @@
+package mysql
+
+import (
+	"database/sql"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestGeneratedColumnToSQL(t *testing.T) {
+	extra := func(s string) JsonNullString {
+		return JsonNullString{sql.NullString{String: s, Valid: true}}
+	}
+	tests := []struct {
+		column   *Column
+		expected string
+	}{
+		{
+			&Column{ColumnName: "full_name", ColumnType: "varchar(129)", Nullable: "YES", Extra: extra("VIRTUAL GENERATED"), GenerationExpression: "concat(`first_name`,' ',`last_name`)"},
+			"`full_name` varchar(129) as (concat(`first_name`,' ',`last_name`)) virtual",
+		},
+		{
+			&Column{ColumnName: "total", ColumnType: "int(11)", Nullable: "NO", Extra: extra("STORED GENERATED"), GenerationExpression: "(`price` * `count`)", ColumnComment: "price * count"},
+			"`total` int(11) as ((`price` * `count`)) stored not null comment 'price * count'",
+		},
+		{
+			// the default value expression of MySQL 8.0 is not a generated column
+			&Column{ColumnName: "created_at", ColumnType: "datetime", Nullable: "NO", ColumnDefault: JsonNullString{sql.NullString{String: "CURRENT_TIMESTAMP", Valid: true}}, Extra: extra("DEFAULT_GENERATED"), GenerationExpression: "now()"},
+			"`created_at` datetime not null default CURRENT_TIMESTAMP DEFAULT_GENERATED",
+		},
+	}
+	for _, test := range tests {
+		if actual := test.column.ToSQL(); actual != test.expected {
+			t.Errorf("err: unexpected SQL.\nexpected %s\nbut actual %s", test.expected, actual)
+		}
+	}
+}
+
+func TestPreviousNamesAreOmitted(t *testing.T) {
+	table := &Table{
+		TableName: "t",
+		Columns:   Columns{{TableName: "t", ColumnName: "c"}},
+		Indices:   Indices{{{Table: "t", KeyName: "PRIMARY", ColumnName: "c"}}},
+	}
+	buf, err := json.Marshal(table)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := string(buf); strings.Contains(s, "PreviousNames") || strings.Contains(s, "PreviousKeyNames") {
+		t.Errorf("err: the empty previous names are exported %s", s)
+	}
+	table.Columns[0].PreviousNames = []string{"old"}
+	if buf, err = json.Marshal(table); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(buf), `"PreviousNames":["old"]`) {
+		t.Errorf("err: the previous names are not exported %s", buf)
+	}
+}
@@ -0,0 +1,129 @@
+package mysql
+
+import (
+	"database/sql"
+	"fmt"
+	"net/url"
+)
+
+// Dialect implements dialect.Dialect for MySQL and MariaDB.
+type Dialect struct{}
+
+func (Dialect) DriverName() string {
+	return "mysql"
+}
+
+// DataSourceName sets the sql_mode of each connection without NO_BACKSLASH_ESCAPES,
+// because the string literals are escaped by backslashes.
+func (Dialect) DataSourceName(dataSource, schema string) string {
+	return fmt.Sprintf("%s/%s?charset=utf8&sql_mode=%s", dataSource, schema, url.QueryEscape(backslashEscapesSQLMode))
+}
+
+func (Dialect) Quote(name string) string {
+	return Quote(name)
+}
+
+func (Dialect) QuoteString(value string) string {
+	return QuoteString(value)
+}
+
+func (Dialect) ForeignKeyCheck(turnOn bool) string {
+	return ForeignKeyCheck(turnOn)
+}
+
+func (Dialect) GetTables(db *sql.DB, schema string, tableNames ...string) (Tables, error) {
+	return GetTables(db, schema, tableNames...)
+}
+
+func (Dialect) GetViews(db *sql.DB, schema string) (Views, error) {
+	return GetViews(db, schema)
+}
+
+func (Dialect) GetTriggers(db *sql.DB, schema string) (Triggers, error) {
+	return GetTriggers(db, schema)
+}
+
+func (Dialect) GetRoutines(db *sql.DB, schema string) (Routines, error) {
+	return GetRoutines(db, schema)
+}
+
+func (Dialect) GetEvents(db *sql.DB, schema string) (Events, error) {
+	return GetEvents(db, schema)
+}
+
+func (Dialect) GetIndices(db *sql.DB, table string) (Indices, error) {
+	return GetIndices(db, table)
+}
+
+func (Dialect) Placeholder(n int) string {
+	return "?"
+}
+
+func (Dialect) ToCreateSQL(table *Table) []string {
+	return []string{table.ToCreateSQL()}
+}
+
+func (Dialect) ToDropSQL(table *Table) string {
+	return table.ToDropSQL()
+}
+
+func (Dialect) ToRenameSQL(table *Table, name string) string {
+	return table.ToRenameSQL(name)
+}
+
+func (Dialect) ToAlterSQL(table *Table, alter *Alter) ([]string, error) {
+	return table.ToAlterSQLs(alter), nil
+}
+
+func (Dialect) ToCreateViewSQL(view *View) string {
+	return view.ToCreateSQL()
+}
+
+func (Dialect) ToDropViewSQL(view *View) string {
+	return view.ToDropSQL()
+}
+
+func (Dialect) ToCreateTriggerSQL(trigger *Trigger) string {
+	return trigger.ToCreateSQL()
+}
+
+func (Dialect) ToDropTriggerSQL(trigger *Trigger) string {
+	return trigger.ToDropSQL()
+}
+
+func (Dialect) ToCreateRoutineSQL(routine *Routine) string {
+	return routine.ToCreateSQL()
+}
+
+func (Dialect) ToDropRoutineSQL(routine *Routine) string {
+	return routine.ToDropSQL()
+}
+
+func (Dialect) ToCreateEventSQL(event *Event) string {
+	return event.ToCreateSQL()
+}
+
+func (Dialect) ToAlterEventSQL(old, new *Event) string {
+	return new.ToAlterSQL(old)
+}
+
+func (Dialect) ToDropEventSQL(event *Event) string {
+	return event.ToDropSQL()
+}
+
+func (Dialect) ToDeleteAllSQL(cnk *Chunk) string {
+	return cnk.ToDeleteAllSQL()
+}
+
+func (Dialect) ToInsertSQL(cnk *Chunk) []string {
+	return cnk.ToInsertSQL()
+}
+
+// ToReplaceSQL ignores keyNames because replace statement of MySQL uses the primary or unique keys of the table.
+func (Dialect) ToReplaceSQL(cnk *Chunk, keyNames []string) []string {
+	return cnk.ToReplaceSQL()
+}
+
+func (Dialect) ToDeleteSQL(cnk *Chunk, colIdxs []int) []string {
+	return cnk.ToDeleteSQL(colIdxs...)
+}
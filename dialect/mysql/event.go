@@ -0,0 +1,182 @@
+package mysql
+
+import (
+	"database/sql"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+type (
+	// Event is a scheduled event of information_schema.EVENTS.
+	// The definer and the time zone are not managed so that the events are created by the user and the time zone of the session.
+	Event struct {
+		EventSchema     string
+		EventName       string
+		EventDefinition string
+		EventType       string
+		ExecuteAt       JsonNullString
+		IntervalValue   JsonNullString
+		IntervalField   JsonNullString
+		Starts          JsonNullString
+		Ends            JsonNullString
+		Status          string
+		OnCompletion    string
+		EventComment    string
+	}
+	Events []*Event
+)
+
+const (
+	EventTypeOneTime   = "ONE TIME"
+	EventTypeRecurring = "RECURRING"
+)
+
+var (
+	createEventSQLFmt string = `create event %s on schedule %s on completion %s %s comment %s do %s`
+	alterEventSQLFmt  string = `alter event %s %s`
+	dropEventSQLFmt   string = `drop event if exists %s`
+	eventStatuses            = map[string]string{
+		"ENABLED":               "enable",
+		"DISABLED":              "disable",
+		"SLAVESIDE_DISABLED":    "disable on slave",
+		"REPLICA_SIDE_DISABLED": "disable on replica",
+	}
+	intervalValueRegexp = regexp.MustCompile(`^\d+$`)
+)
+
+func (m *Event) GetFormatedEventName() string {
+	return Quote(m.EventName)
+}
+
+// ToScheduleSQL returns the schedule like "at '2017-01-01 00:00:00'" or "every 1 day starts '2017-01-01 00:00:00'".
+func (m *Event) ToScheduleSQL() string {
+	if m.EventType == EventTypeOneTime {
+		return fmt.Sprintf("at %s", QuoteString(m.ExecuteAt.String))
+	}
+	value := m.IntervalValue.String
+	if !intervalValueRegexp.MatchString(value) {
+		// the value of the composite field like DAY_HOUR is written as '1 12'
+		value = QuoteString(value)
+	}
+	token := []string{"every", value, strings.ToLower(m.IntervalField.String)}
+	if m.Starts.Valid {
+		token = append(token, "starts", QuoteString(m.Starts.String))
+	}
+	if m.Ends.Valid {
+		token = append(token, "ends", QuoteString(m.Ends.String))
+	}
+	return strings.Join(token, " ")
+}
+
+func (m *Event) ToStatusSQL() string {
+	if status, ok := eventStatuses[strings.ToUpper(m.Status)]; ok {
+		return status
+	}
+	return "enable"
+}
+
+func (m *Event) ToOnCompletionSQL() string {
+	if m.OnCompletion == "" {
+		return "not preserve"
+	}
+	return strings.ToLower(m.OnCompletion)
+}
+
+func (m *Event) ToCreateSQL() string {
+	return fmt.Sprintf(createEventSQLFmt, m.GetFormatedEventName(), m.ToScheduleSQL(), m.ToOnCompletionSQL(), m.ToStatusSQL(), QuoteString(m.EventComment), m.EventDefinition)
+}
+
+// ToAlterSQL returns the alter event statement which changes only the clauses that differ from the old event,
+// or the empty string when both events are the same.
+func (m *Event) ToAlterSQL(old *Event) string {
+	clauses := []string{}
+	if m.ToScheduleSQL() != old.ToScheduleSQL() {
+		clauses = append(clauses, "on schedule "+m.ToScheduleSQL())
+	}
+	if m.ToOnCompletionSQL() != old.ToOnCompletionSQL() {
+		clauses = append(clauses, "on completion "+m.ToOnCompletionSQL())
+	}
+	if m.ToStatusSQL() != old.ToStatusSQL() {
+		clauses = append(clauses, m.ToStatusSQL())
+	}
+	if m.EventComment != old.EventComment {
+		clauses = append(clauses, "comment "+QuoteString(m.EventComment))
+	}
+	if m.EventDefinition != old.EventDefinition {
+		clauses = append(clauses, "do "+m.EventDefinition)
+	}
+	if len(clauses) <= 0 {
+		return ""
+	}
+	return fmt.Sprintf(alterEventSQLFmt, m.GetFormatedEventName(), strings.Join(clauses, " "))
+}
+
+func (m *Event) ToDropSQL() string {
+	return fmt.Sprintf(dropEventSQLFmt, m.GetFormatedEventName())
+}
+
+func (m Events) GroupByEventName() map[string]*Event {
+	nameMap := make(map[string]*Event, len(m))
+	for _, event := range m {
+		nameMap[event.EventName] = event
+	}
+	return nameMap
+}
+
+func (m Events) GetSortedEventNames() []string {
+	names := make([]string, 0, len(m))
+	for _, event := range m {
+		names = append(names, event.EventName)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// GetEvents returns the scheduled events of the schema.
+func GetEvents(db *sql.DB, schema string) (Events, error) {
+	selectCols := []string{
+		"EVENT_SCHEMA",
+		"EVENT_NAME",
+		"EVENT_DEFINITION",
+		"EVENT_TYPE",
+		"EXECUTE_AT",
+		"INTERVAL_VALUE",
+		"INTERVAL_FIELD",
+		"STARTS",
+		"ENDS",
+		"STATUS",
+		"ON_COMPLETION",
+		"EVENT_COMMENT",
+	}
+	query := fmt.Sprintf(`select %s from information_schema.EVENTS where EVENT_SCHEMA=%s`, strings.Join(selectCols, ","), QuoteString(schema))
+	rows, err := db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("err: db.Query `%s' failed for reason %s", query, err)
+	}
+	defer rows.Close()
+
+	events := Events{}
+	for rows.Next() {
+		event := &Event{}
+		if err := rows.Scan(
+			&event.EventSchema,
+			&event.EventName,
+			&event.EventDefinition,
+			&event.EventType,
+			&event.ExecuteAt,
+			&event.IntervalValue,
+			&event.IntervalField,
+			&event.Starts,
+			&event.Ends,
+			&event.Status,
+			&event.OnCompletion,
+			&event.EventComment,
+		); err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+	return events, rows.Err()
+}
@@ -0,0 +1,95 @@
+package mysql
+
+import (
+	"database/sql"
+	"testing"
+)
+
+func nullString(s string) JsonNullString {
+	return JsonNullString{sql.NullString{String: s, Valid: true}}
+}
+
+func makePurgeEvent() *Event {
+	return &Event{
+		EventName:       "purge_log",
+		EventDefinition: "delete from `log` where `created_at` < now() - interval 30 day",
+		EventType:       EventTypeRecurring,
+		IntervalValue:   nullString("1"),
+		IntervalField:   nullString("DAY"),
+		Starts:          nullString("2017-01-01 00:00:00"),
+		Status:          "ENABLED",
+		OnCompletion:    "NOT PRESERVE",
+		EventComment:    "purge old logs",
+	}
+}
+
+func TestEventToCreateSQL(t *testing.T) {
+	recurring := makePurgeEvent()
+	composite := makePurgeEvent()
+	composite.IntervalValue = nullString("1 12")
+	composite.IntervalField = nullString("DAY_HOUR")
+	composite.Ends = nullString("2018-01-01 00:00:00")
+	composite.Status = "SLAVESIDE_DISABLED"
+	oneTime := &Event{
+		EventName:       "cleanup",
+		EventDefinition: "BEGIN\n\tdelete from `tmp`;\n\tdelete from `tmp2`;\nEND",
+		EventType:       EventTypeOneTime,
+		ExecuteAt:       nullString("2017-06-01 03:00:00"),
+		Status:          "DISABLED",
+		OnCompletion:    "PRESERVE",
+		EventComment:    "it's once",
+	}
+	tests := []struct {
+		event    *Event
+		expected string
+	}{
+		{recurring, "create event `purge_log` on schedule every 1 day starts '2017-01-01 00:00:00' on completion not preserve enable comment 'purge old logs' do delete from `log` where `created_at` < now() - interval 30 day"},
+		{composite, "create event `purge_log` on schedule every '1 12' day_hour starts '2017-01-01 00:00:00' ends '2018-01-01 00:00:00' on completion not preserve disable on slave comment 'purge old logs' do delete from `log` where `created_at` < now() - interval 30 day"},
+		{oneTime, "create event `cleanup` on schedule at '2017-06-01 03:00:00' on completion preserve disable comment 'it''s once' do BEGIN\n\tdelete from `tmp`;\n\tdelete from `tmp2`;\nEND"},
+	}
+	for _, test := range tests {
+		if actual := test.event.ToCreateSQL(); actual != test.expected {
+			t.Errorf("err: unexpected SQL.\nexpected %s\nbut actual %s", test.expected, actual)
+		}
+	}
+	if actual := recurring.ToDropSQL(); actual != "drop event if exists `purge_log`" {
+		t.Errorf("err: unexpected SQL %s", actual)
+	}
+}
+
+func TestEventToAlterSQL(t *testing.T) {
+	tests := []struct {
+		modify   func(e *Event)
+		expected string
+	}{
+		{func(e *Event) {}, ""},
+		{func(e *Event) { e.EventSchema = "another" }, ""},
+		{
+			func(e *Event) { e.IntervalValue = nullString("6"); e.IntervalField = nullString("HOUR") },
+			"alter event `purge_log` on schedule every 6 hour starts '2017-01-01 00:00:00'",
+		},
+		{
+			func(e *Event) { e.EventType = EventTypeOneTime; e.ExecuteAt = nullString("2017-06-01 03:00:00") },
+			"alter event `purge_log` on schedule at '2017-06-01 03:00:00'",
+		},
+		{func(e *Event) { e.Status = "DISABLED" }, "alter event `purge_log` disable"},
+		{func(e *Event) { e.OnCompletion = "PRESERVE" }, "alter event `purge_log` on completion preserve"},
+		{func(e *Event) { e.EventComment = "" }, "alter event `purge_log` comment ''"},
+		{
+			func(e *Event) { e.EventDefinition = "delete from `log` where `created_at` < now() - interval 7 day" },
+			"alter event `purge_log` do delete from `log` where `created_at` < now() - interval 7 day",
+		},
+		{
+			func(e *Event) { e.Status = "DISABLED"; e.EventComment = "stopped" },
+			"alter event `purge_log` disable comment 'stopped'",
+		},
+	}
+	for _, test := range tests {
+		old := makePurgeEvent()
+		new := makePurgeEvent()
+		test.modify(new)
+		if actual := new.ToAlterSQL(old); actual != test.expected {
+			t.Errorf("err: unexpected SQL.\nexpected %s\nbut actual %s", test.expected, actual)
+		}
+	}
+}
@@ -0,0 +1,187 @@
+package mysql
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+type (
+	ForeignKey struct {
+		ConstraintName        string
+		TableSchema           string
+		TableName             string
+		ColumnNames           []string
+		ReferencedTableSchema string
+		ReferencedTableName   string
+		ReferencedColumnNames []string
+		UpdateRule            string
+		DeleteRule            string
+	}
+	ForeignKeys []*ForeignKey
+)
+
+func (m *ForeignKey) GetFormatedReferencedTableName() string {
+	if !m.IsExternalReferenced() {
+		return Quote(m.ReferencedTableName)
+	}
+	return fmt.Sprintf("%s.%s", Quote(m.ReferencedTableSchema), Quote(m.ReferencedTableName))
+}
+
+func (m *ForeignKey) IsExternalReferenced() bool {
+	return m.ReferencedTableSchema != "" && m.ReferencedTableSchema != m.TableSchema
+}
+
+func (m *ForeignKey) IsSelfReferenced() bool {
+	return !m.IsExternalReferenced() && m.ReferencedTableName == m.TableName
+}
+
+func (m *ForeignKey) ToSQL() string {
+	token := []string{
+		"constraint", Quote(m.ConstraintName),
+		fmt.Sprintf("foreign key (%s)", strings.Join(QuoteMulti(m.ColumnNames), ",")),
+		fmt.Sprintf("references %s (%s)", m.GetFormatedReferencedTableName(), strings.Join(QuoteMulti(m.ReferencedColumnNames), ",")),
+	}
+	if m.DeleteRule != "" {
+		token = append(token, "on delete", strings.ToLower(m.DeleteRule))
+	}
+	if m.UpdateRule != "" {
+		token = append(token, "on update", strings.ToLower(m.UpdateRule))
+	}
+	return strings.Join(token, " ")
+}
+
+func (m *ForeignKey) ToAddSQL() string {
+	return fmt.Sprintf("add %s", m.ToSQL())
+}
+
+func (m *ForeignKey) ToDropSQL() string {
+	return fmt.Sprintf("drop foreign key %s", Quote(m.ConstraintName))
+}
+
+// Equal reports whether both foreign keys generate the same definition.
+// Schema names are ignored unless the key refers to another schema.
+func (m *ForeignKey) Equal(fk *ForeignKey) bool {
+	return m.ToSQL() == fk.ToSQL()
+}
+
+func (m ForeignKeys) ToSQL() []string {
+	sqls := make([]string, 0, len(m))
+	for _, fk := range m.getSortedForeignKeys() {
+		sqls = append(sqls, fk.ToSQL())
+	}
+	return sqls
+}
+
+func (m ForeignKeys) ToAddSQL() []string {
+	sqls := make([]string, 0, len(m))
+	for _, fk := range m.getSortedForeignKeys() {
+		sqls = append(sqls, fk.ToAddSQL())
+	}
+	return sqls
+}
+
+func (m ForeignKeys) ToDropSQL() []string {
+	sqls := make([]string, 0, len(m))
+	for _, fk := range m.getSortedForeignKeys() {
+		sqls = append(sqls, fk.ToDropSQL())
+	}
+	return sqls
+}
+
+func (m ForeignKeys) GroupByConstraintName() map[string]*ForeignKey {
+	nameMap := make(map[string]*ForeignKey, len(m))
+	for _, fk := range m {
+		nameMap[fk.ConstraintName] = fk
+	}
+	return nameMap
+}
+
+func (m ForeignKeys) GetSortedConstraintNames() []string {
+	names := make([]string, 0, len(m))
+	for _, fk := range m {
+		names = append(names, fk.ConstraintName)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// GetReferencedTableNames returns the sorted names of the tables referred by the foreign keys.
+// Self references and references to other schemas are excluded.
+func (m ForeignKeys) GetReferencedTableNames() []string {
+	nameMap := map[string]struct{}{}
+	for _, fk := range m {
+		if fk.IsSelfReferenced() || fk.IsExternalReferenced() {
+			continue
+		}
+		nameMap[fk.ReferencedTableName] = struct{}{}
+	}
+	names := make([]string, 0, len(nameMap))
+	for name := range nameMap {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (m ForeignKeys) getSortedForeignKeys() ForeignKeys {
+	fkMap := m.GroupByConstraintName()
+	fks := make(ForeignKeys, 0, len(m))
+	for _, name := range m.GetSortedConstraintNames() {
+		fks = append(fks, fkMap[name])
+	}
+	return fks
+}
+
+func GetForeignKeys(db *sql.DB, schema string) (ForeignKeys, error) {
+	selectCols := []string{
+		"kcu.CONSTRAINT_NAME",
+		"kcu.TABLE_SCHEMA",
+		"kcu.TABLE_NAME",
+		"kcu.COLUMN_NAME",
+		"kcu.REFERENCED_TABLE_SCHEMA",
+		"kcu.REFERENCED_TABLE_NAME",
+		"kcu.REFERENCED_COLUMN_NAME",
+		"rc.UPDATE_RULE",
+		"rc.DELETE_RULE",
+	}
+	query := fmt.Sprintf(`select %s from information_schema.KEY_COLUMN_USAGE kcu
+	inner join information_schema.REFERENTIAL_CONSTRAINTS rc
+		on rc.CONSTRAINT_SCHEMA=kcu.CONSTRAINT_SCHEMA and rc.TABLE_NAME=kcu.TABLE_NAME and rc.CONSTRAINT_NAME=kcu.CONSTRAINT_NAME
+	where kcu.TABLE_SCHEMA=%s and kcu.REFERENCED_TABLE_NAME is not null
+	order by kcu.TABLE_NAME, kcu.CONSTRAINT_NAME, kcu.ORDINAL_POSITION`, strings.Join(selectCols, ","), QuoteString(schema))
+	rows, err := db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("err: db.Query `%s' failed for reason %s", query, err)
+	}
+	defer rows.Close()
+
+	fks := ForeignKeys{}
+	fkMap := map[string]*ForeignKey{}
+	for rows.Next() {
+		var columnName, referencedColumnName string
+		fk := &ForeignKey{}
+		if err := rows.Scan(
+			&fk.ConstraintName,
+			&fk.TableSchema,
+			&fk.TableName,
+			&columnName,
+			&fk.ReferencedTableSchema,
+			&fk.ReferencedTableName,
+			&referencedColumnName,
+			&fk.UpdateRule,
+			&fk.DeleteRule,
+		); err != nil {
+			return nil, err
+		}
+		key := fmt.Sprintf("%s.%s", fk.TableName, fk.ConstraintName)
+		if _, ok := fkMap[key]; !ok {
+			fkMap[key] = fk
+			fks = append(fks, fk)
+		}
+		fkMap[key].ColumnNames = append(fkMap[key].ColumnNames, columnName)
+		fkMap[key].ReferencedColumnNames = append(fkMap[key].ReferencedColumnNames, referencedColumnName)
+	}
+	return fks, nil
+}
@@ -3,29 +3,44 @@ package mysql
 import (
 	"database/sql"
 	"fmt"
+	"regexp"
 	"sort"
 	"strings"
 )
 
 type (
 	IndexColumn struct {
-		Table        string
-		NonUniue     int8
-		KeyName      string
-		SeqInIndex   int32
-		ColumnName   string
-		Collation    string
-		SubPart      JsonNullString
-		Packed       JsonNullString
-		Null         string
-		IndexType    string
-		Comment      string
-		IndexComment string
+		Table            string
+		NonUniue         int8
+		KeyName          string
+		SeqInIndex       int32
+		ColumnName       string
+		Collation        string
+		SubPart          JsonNullString
+		Packed           JsonNullString
+		Null             string
+		IndexType        string
+		Comment          string
+		IndexComment     string
+		Expression       JsonNullString
+		Parser           string
+		Visible          string
+		PreviousKeyNames []string `json:",omitempty"`
 	}
 	Index   []IndexColumn
 	Indices []Index
 )
 
+const (
+	IndexTypeFulltext = "FULLTEXT"
+	IndexTypeSpatial  = "SPATIAL"
+	IndexTypeHash     = "HASH"
+)
+
+var (
+	fulltextKeyRegexp = regexp.MustCompile("^\\s*FULLTEXT KEY `((?:[^`]|``)+)` .*WITH PARSER `(\\w+)`")
+)
+
 func (m Index) IsPrimaryKey() bool {
 	return m[0].KeyName == "PRIMARY"
 }
@@ -34,14 +49,105 @@ func (m Index) IsUniqueKey() bool {
 	return m[0].NonUniue != 1
 }
 
+func (m Index) IsFulltextKey() bool {
+	return strings.EqualFold(m[0].IndexType, IndexTypeFulltext)
+}
+
+func (m Index) IsSpatialKey() bool {
+	return strings.EqualFold(m[0].IndexType, IndexTypeSpatial)
+}
+
+// HasExpression reports whether the index has a functional key part of MySQL 8.0.13 or later.
+func (m Index) HasExpression() bool {
+	for _, info := range m {
+		if info.Expression.Valid {
+			return true
+		}
+	}
+	return false
+}
+
+// IsVisible reports whether the index is visible to the optimizer.
+// Visible is "NO" for the invisible index of MySQL 8.0 and empty for the servers which do not have invisible indices.
+func (m Index) IsVisible() bool {
+	return m[0].Visible != "NO"
+}
+
 func (m Index) GetKeyName() string {
 	return m[0].KeyName
 }
 
+func (m Index) HasPreviousKeyName(name string) bool {
+	for _, n := range m[0].PreviousKeyNames {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// WithKeyName returns a copy of the index which is named as the specified name.
+func (m Index) WithKeyName(name string) Index {
+	index := make(Index, 0, len(m))
+	for _, info := range m {
+		info.KeyName = name
+		index = append(index, info)
+	}
+	return index
+}
+
+// WithVisibility returns a copy of the index whose visibility is the specified one.
+func (m Index) WithVisibility(visible bool) Index {
+	v := "YES"
+	if !visible {
+		v = "NO"
+	}
+	index := make(Index, 0, len(m))
+	for _, info := range m {
+		info.Visible = v
+		index = append(index, info)
+	}
+	return index
+}
+
+// EqualDefinition reports whether both indices have the same definition except for the key name.
+func (m Index) EqualDefinition(idx Index) bool {
+	if m.IsPrimaryKey() || idx.IsPrimaryKey() {
+		return false
+	}
+	return Indices{m}.ToSQL()[0] == Indices{idx.WithKeyName(m.GetKeyName())}.ToSQL()[0]
+}
+
+func (m Index) ToRenameSQL(name string) string {
+	return fmt.Sprintf("rename index %s to %s", Quote(m.GetKeyName()), Quote(name))
+}
+
+func (m Index) ToAlterVisibilitySQL() string {
+	if m.IsVisible() {
+		return fmt.Sprintf("alter index %s visible", Quote(m.GetKeyName()))
+	}
+	return fmt.Sprintf("alter index %s invisible", Quote(m.GetKeyName()))
+}
+
+// ToKeyPartSQL returns the key part which is the quoted column name with the prefix length or the expression enclosed in parentheses,
+// followed by desc when Collation is "D".
+func (m IndexColumn) ToKeyPartSQL(withSubPart bool) string {
+	part := Quote(m.ColumnName)
+	if m.Expression.Valid {
+		part = fmt.Sprintf("(%s)", m.Expression.String)
+	} else if withSubPart && m.SubPart.Valid {
+		part = fmt.Sprintf("%s(%s)", part, m.SubPart.String)
+	}
+	if m.Collation == "D" {
+		part = fmt.Sprintf("%s desc", part)
+	}
+	return part
+}
+
 func (m Index) ColumnNames() []string {
 	names := make([]string, 0, len(m))
 	for _, info := range m {
-		names = append(names, Quote(info.ColumnName))
+		names = append(names, info.ToKeyPartSQL(false))
 	}
 	return names
 }
@@ -49,11 +155,7 @@ func (m Index) ColumnNames() []string {
 func (m Index) KeyNamesWithSubPart() []string {
 	names := make([]string, 0, len(m))
 	for _, info := range m {
-		if info.SubPart.Valid {
-			names = append(names, fmt.Sprintf("%s(%s)", Quote(info.ColumnName), info.SubPart.String))
-		} else {
-			names = append(names, Quote(info.ColumnName))
-		}
+		names = append(names, info.ToKeyPartSQL(true))
 	}
 	return names
 }
@@ -66,15 +168,29 @@ func (m Indices) ToSQL() []string {
 		comment := index[0].Comment
 		switch {
 		case index.IsPrimaryKey():
-			sql = fmt.Sprintf("primary key (%s)", strings.Join(index.ColumnNames(), ","))
+			sql = fmt.Sprintf("primary key (%s)", strings.Join(index.KeyNamesWithSubPart(), ","))
 		case !index.IsPrimaryKey() && index.IsUniqueKey():
-			sql = fmt.Sprintf("unique key %s (%s)", Quote(index.GetKeyName()), strings.Join(index.ColumnNames(), ","))
+			sql = fmt.Sprintf("unique key %s (%s)", Quote(index.GetKeyName()), strings.Join(index.KeyNamesWithSubPart(), ","))
+		case index.IsFulltextKey():
+			sql = fmt.Sprintf("fulltext key %s (%s)", Quote(index.GetKeyName()), strings.Join(index.KeyNamesWithSubPart(), ","))
+			if parser := index[0].Parser; parser != "" {
+				sql = fmt.Sprintf("%s with parser %s", sql, parser)
+			}
+		case index.IsSpatialKey():
+			// show index reports the prefix length of SPATIAL key, which can not be specified
+			sql = fmt.Sprintf("spatial key %s (%s)", Quote(index.GetKeyName()), strings.Join(index.ColumnNames(), ","))
 		default:
 			sql = fmt.Sprintf("key %s (%s)", Quote(index.GetKeyName()), strings.Join(index.KeyNamesWithSubPart(), ","))
 		}
+		if !index.IsPrimaryKey() && strings.EqualFold(index[0].IndexType, IndexTypeHash) {
+			sql = fmt.Sprintf("%s using hash", sql)
+		}
 		if comment != "" {
 			sql = fmt.Sprintf("%s comment %s", sql, QuoteString(comment))
 		}
+		if !index.IsVisible() {
+			sql = fmt.Sprintf("%s invisible", sql)
+		}
 		indexSQLs = append(indexSQLs, sql)
 	}
 	return indexSQLs
@@ -100,6 +216,20 @@ func (m Indices) ToDropSQL() []string {
 	return sqls
 }
 
+// WithoutPreviousKeyNames returns a copy of the indices which has no rename hint.
+func (m Indices) WithoutPreviousKeyNames() Indices {
+	indices := make(Indices, 0, len(m))
+	for _, index := range m {
+		idx := make(Index, 0, len(index))
+		for _, info := range index {
+			info.PreviousKeyNames = nil
+			idx = append(idx, info)
+		}
+		indices = append(indices, idx)
+	}
+	return indices
+}
+
 func (m Indices) GroupByKeyName() map[string]Indices {
 	nameMap := make(map[string]Indices, len(m))
 	for _, index := range m {
@@ -147,43 +277,95 @@ func (m Indices) getSortedIndices(keys []string) Indices {
 	return indices
 }
 
+// GetIndices returns the indices of the table read by show index.
+// The columns are scanned by the names, because the newer servers return more columns like Visible and Expression.
 func GetIndices(db *sql.DB, table string) (Indices, error) {
-	query := fmt.Sprintf("show index from `%s`", table)
+	query := fmt.Sprintf("show index from %s", Quote(table))
 	rows, err := db.Query(query)
 	if err != nil {
 		return nil, fmt.Errorf("err: db.Query faild `%s' for reason %s", query, err)
 	}
 	defer rows.Close()
 
-	dummy := JsonNullInt64{}
+	cols, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
 	idxMap := map[string]Index{}
+	hasFulltext := false
 	for rows.Next() {
 		idxCol := IndexColumn{}
-		if err := rows.Scan(
-			&idxCol.Table,
-			&idxCol.NonUniue,
-			&idxCol.KeyName,
-			&idxCol.SeqInIndex,
-			&idxCol.ColumnName,
-			&idxCol.Collation,
-			&dummy,
-			&idxCol.SubPart,
-			&idxCol.Packed,
-			&idxCol.Null,
-			&idxCol.IndexType,
-			&idxCol.IndexComment,
-			&idxCol.Comment,
-		); err != nil {
+		// Column_name of the functional key part and Collation of FULLTEXT key are NULL
+		var columnName, collation sql.NullString
+		dests := map[string]interface{}{
+			"table":         &idxCol.Table,
+			"non_unique":    &idxCol.NonUniue,
+			"key_name":      &idxCol.KeyName,
+			"seq_in_index":  &idxCol.SeqInIndex,
+			"column_name":   &columnName,
+			"collation":     &collation,
+			"sub_part":      &idxCol.SubPart,
+			"packed":        &idxCol.Packed,
+			"null":          &idxCol.Null,
+			"index_type":    &idxCol.IndexType,
+			"comment":       &idxCol.IndexComment,
+			"index_comment": &idxCol.Comment,
+			"expression":    &idxCol.Expression,
+			"visible":       &idxCol.Visible,
+		}
+		args := make([]interface{}, 0, len(cols))
+		for _, col := range cols {
+			if dest, ok := dests[strings.ToLower(col)]; ok {
+				args = append(args, dest)
+			} else {
+				args = append(args, new(interface{}))
+			}
+		}
+		if err := rows.Scan(args...); err != nil {
 			return nil, err
 		}
+		idxCol.ColumnName = columnName.String
+		idxCol.Collation = collation.String
+		if strings.EqualFold(idxCol.IndexType, IndexTypeFulltext) {
+			hasFulltext = true
+		}
 		if _, ok := idxMap[idxCol.KeyName]; !ok {
 			idxMap[idxCol.KeyName] = Index{}
 		}
 		idxMap[idxCol.KeyName] = append(idxMap[idxCol.KeyName], idxCol)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if hasFulltext {
+		// the parser of FULLTEXT key is only shown by show create table
+		query := fmt.Sprintf("show create table %s", Quote(table))
+		var name, createSQL string
+		if err := db.QueryRow(query).Scan(&name, &createSQL); err != nil {
+			return nil, fmt.Errorf("err: db.QueryRow `%s' failed for reason %s", query, err)
+		}
+		for keyName, parser := range parseFulltextParsers(createSQL) {
+			index := idxMap[keyName]
+			for i := range index {
+				index[i].Parser = parser
+			}
+		}
+	}
 	indices := make(Indices, 0, len(idxMap))
 	for _, index := range idxMap {
 		indices = append(indices, index)
 	}
 	return indices.getSortedIndices(indices.GetSortedKeys()), nil
 }
+
+// parseFulltextParsers returns the parsers of FULLTEXT keys by the key names from the result of show create table like
+// FULLTEXT KEY `ft_body` (`body`) /*!50100 WITH PARSER `ngram` */
+func parseFulltextParsers(createSQL string) map[string]string {
+	parsers := map[string]string{}
+	for _, line := range strings.Split(createSQL, "\n") {
+		if matches := fulltextKeyRegexp.FindStringSubmatch(line); matches != nil {
+			parsers[strings.Replace(matches[1], "``", "`", -1)] = matches[2]
+		}
+	}
+	return parsers
+}
@@ -0,0 +1,57 @@
+package mysql
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestIndicesToSQL(t *testing.T) {
+	indices := Indices{
+		{{Table: "article", NonUniue: 0, KeyName: "PRIMARY", SeqInIndex: 1, ColumnName: "id", IndexType: "BTREE"}},
+		{
+			{Table: "article", NonUniue: 1, KeyName: "ft_title_body", SeqInIndex: 1, ColumnName: "title", IndexType: "FULLTEXT", Parser: "ngram"},
+			{Table: "article", NonUniue: 1, KeyName: "ft_title_body", SeqInIndex: 2, ColumnName: "body", IndexType: "FULLTEXT", Parser: "ngram"},
+		},
+		{{Table: "article", NonUniue: 1, KeyName: "ft_tag", SeqInIndex: 1, ColumnName: "tag", IndexType: "FULLTEXT"}},
+		{{Table: "article", NonUniue: 1, KeyName: "sp_location", SeqInIndex: 1, ColumnName: "location", SubPart: nullString("32"), IndexType: "SPATIAL"}},
+		{
+			{Table: "article", NonUniue: 1, KeyName: "k_lower_title", SeqInIndex: 1, Expression: nullString("lower(`title`)"), IndexType: "BTREE"},
+			{Table: "article", NonUniue: 1, KeyName: "k_lower_title", SeqInIndex: 2, ColumnName: "id", IndexType: "BTREE"},
+		},
+		{{Table: "article", NonUniue: 0, KeyName: "u_code", SeqInIndex: 1, ColumnName: "code", IndexType: "HASH", Comment: "code"}},
+		{
+			{Table: "article", NonUniue: 0, KeyName: "u_title_created", SeqInIndex: 1, ColumnName: "title", Collation: "A", SubPart: nullString("16"), IndexType: "BTREE", Visible: "YES"},
+			{Table: "article", NonUniue: 0, KeyName: "u_title_created", SeqInIndex: 2, ColumnName: "created_at", Collation: "D", IndexType: "BTREE", Visible: "YES"},
+		},
+		{{Table: "article", NonUniue: 1, KeyName: "k_tag", SeqInIndex: 1, ColumnName: "tag", Collation: "A", IndexType: "BTREE", Visible: "NO"}},
+	}
+	expected := []string{
+		"primary key (`id`)",
+		"unique key `u_code` (`code`) using hash comment 'code'",
+		"unique key `u_title_created` (`title`(16),`created_at` desc)",
+		"fulltext key `ft_tag` (`tag`)",
+		"fulltext key `ft_title_body` (`title`,`body`) with parser ngram",
+		"key `k_lower_title` ((lower(`title`)),`id`)",
+		"key `k_tag` (`tag`) invisible",
+		"spatial key `sp_location` (`location`)",
+	}
+	if actual := indices.ToSQL(); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("err: unexpected SQL.\nexpected %q\nbut actual %q", expected, actual)
+	}
+}
+
+func TestParseFulltextParsers(t *testing.T) {
+	createSQL := "CREATE TABLE `article` (\n" +
+		"  `id` int NOT NULL,\n" +
+		"  `title` varchar(255) NOT NULL,\n" +
+		"  `body` text NOT NULL,\n" +
+		"  `tag` varchar(32) NOT NULL,\n" +
+		"  PRIMARY KEY (`id`),\n" +
+		"  FULLTEXT KEY `ft_tag` (`tag`),\n" +
+		"  FULLTEXT KEY `ft_title_body` (`title`,`body`) /*!50100 WITH PARSER `ngram` */ \n" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4"
+	expected := map[string]string{"ft_title_body": "ngram"}
+	if actual := parseFulltextParsers(createSQL); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("err: unexpected parsers.\nexpected %v\nbut actual %v", expected, actual)
+	}
+}
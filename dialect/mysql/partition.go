@@ -28,30 +28,173 @@ type (
 )
 
 const (
-	PartitionMethodLinearKey  = "LINEAR KEY"
-	PartitionMethodLinearHash = "LINEAR HASH"
-	PartitionMethodRange      = "RANGE COLUMNS"
+	// PartitionMethodRange keeps RANGE COLUMNS of the earlier versions,
+	// and PartitionMethodRangeExpr is RANGE of an expression like to_days(`created_at`).
+	PartitionMethodRange       = "RANGE COLUMNS"
+	PartitionMethodRangeExpr   = "RANGE"
+	PartitionMethodList        = "LIST"
+	PartitionMethodListColumns = "LIST COLUMNS"
+	PartitionMethodHash        = "HASH"
+	PartitionMethodLinearHash  = "LINEAR HASH"
+	PartitionMethodKey         = "KEY"
+	PartitionMethodLinearKey   = "LINEAR KEY"
 )
 
+var (
+	partitionMethods = map[string]struct{}{
+		PartitionMethodRange:       {},
+		PartitionMethodRangeExpr:   {},
+		PartitionMethodList:        {},
+		PartitionMethodListColumns: {},
+		PartitionMethodHash:        {},
+		PartitionMethodLinearHash:  {},
+		PartitionMethodKey:         {},
+		PartitionMethodLinearKey:   {},
+	}
+)
+
+// IsHashOrKey reports whether the partitions are defined by the number instead of the values.
+func (m *Partition) IsHashOrKey() bool {
+	switch m.PartitionMethod {
+	case PartitionMethodHash, PartitionMethodLinearHash, PartitionMethodKey, PartitionMethodLinearKey:
+		return true
+	}
+	return false
+}
+
+func (m *Partition) IsSubpartitioned() bool {
+	return m.SubpartitionMethod.Valid && m.SubpartitionMethod.String != ""
+}
+
+// ToValuesSQL returns the values clause of RANGE or LIST partition.
+// PartitionDescription is written as it is, like "10", "'2017-01-01'" or "MAXVALUE".
+func (m *Partition) ToValuesSQL() string {
+	switch m.PartitionMethod {
+	case PartitionMethodRangeExpr:
+		if m.PartitionDescription.String == "MAXVALUE" {
+			return "values less than MAXVALUE"
+		}
+		return fmt.Sprintf("values less than (%s)", m.PartitionDescription.String)
+	case PartitionMethodRange:
+		return fmt.Sprintf("values less than (%s)", m.PartitionDescription.String)
+	case PartitionMethodList, PartitionMethodListColumns:
+		return fmt.Sprintf("values in (%s)", m.PartitionDescription.String)
+	}
+	return ""
+}
+
+func toPartitionMethodSQL(method, expression string) string {
+	if _, ok := partitionMethods[method]; !ok {
+		return ""
+	}
+	return fmt.Sprintf("%s (%s)", strings.ToLower(method), expression)
+}
+
+// ToSQL returns the partition clause of the table.
+// The partitions have a row for each subpartition like information_schema.PARTITIONS.
 func (m Partitions) ToSQL() string {
-	if m == nil {
+	if len(m) <= 0 {
 		return ""
 	}
-	// only for support "LINEAR KEY" or "LINEAR HASH" or "RANGE COLUMNS" partition method
-	switch m[0].PartitionMethod {
-	case PartitionMethodLinearKey:
-		return fmt.Sprintf("partition by linear key (%s) partitions %d", m[0].PartitionExpression, len(m))
-	case PartitionMethodLinearHash:
-		return fmt.Sprintf("partition by linear hash (%s) partitions %d", m[0].PartitionExpression, len(m))
-	case PartitionMethodRange:
-		sqls := make([]string, 0, len(m))
-		for _, partition := range m {
-			sqls = append(sqls, fmt.Sprintf("\t\tpartition %s values less than (%s)", partition.PartitionName, partition.PartitionDescription.String))
-		}
-		return fmt.Sprintf("partition by range columns (%s) (\n%s\n\t)", m[0].PartitionExpression, strings.Join(sqls, ",\n"))
-	default:
+	sql := toPartitionMethodSQL(m[0].PartitionMethod, m[0].PartitionExpression)
+	if sql == "" {
 		return ""
 	}
+	sql = fmt.Sprintf("partition by %s", sql)
+	if m[0].IsSubpartitioned() {
+		subSQL := toPartitionMethodSQL(m[0].SubpartitionMethod.String, m[0].SubpartitionExpression.String)
+		if subSQL == "" {
+			return ""
+		}
+		sql = fmt.Sprintf("%s subpartition by %s", sql, subSQL)
+	}
+	if m[0].IsHashOrKey() {
+		return fmt.Sprintf("%s partitions %d", sql, len(m))
+	}
+	return fmt.Sprintf("%s %s", sql, m.toDefinitionsSQL())
+}
+
+// EqualScheme reports whether both partitions have the same partitioning method, subpartitioning method and expressions,
+// so that the partitions can be added, dropped or reorganized without repartitioning the table.
+func (m Partitions) EqualScheme(p Partitions) bool {
+	if len(m) <= 0 || len(p) <= 0 {
+		return false
+	}
+	a, b := m[0], p[0]
+	return a.PartitionMethod == b.PartitionMethod &&
+		a.PartitionExpression == b.PartitionExpression &&
+		a.IsSubpartitioned() == b.IsSubpartitioned() &&
+		a.SubpartitionMethod.String == b.SubpartitionMethod.String &&
+		a.SubpartitionExpression.String == b.SubpartitionExpression.String
+}
+
+// ToDefinitionSQL returns the definition of the partition whose subpartitions are the specified partitions.
+func (m Partitions) ToDefinitionSQL() string {
+	p := m[0]
+	sql := fmt.Sprintf("\t\tpartition %s %s", Quote(p.PartitionName), p.ToValuesSQL())
+	if !p.IsSubpartitioned() {
+		if p.PartitionComment != "" {
+			sql = fmt.Sprintf("%s comment %s", sql, QuoteString(p.PartitionComment))
+		}
+		return sql
+	}
+	subs := make([]string, 0, len(m))
+	for _, sub := range m {
+		subs = append(subs, fmt.Sprintf("\t\t\tsubpartition %s", Quote(sub.SubpartitionName.String)))
+	}
+	return fmt.Sprintf("%s (\n%s\n\t\t)", sql, strings.Join(subs, ",\n"))
+}
+
+func (m Partitions) toDefinitionsSQL() string {
+	groups := m.GroupByPartitionName()
+	defs := make([]string, 0, len(groups))
+	for _, partitions := range groups {
+		defs = append(defs, partitions.ToDefinitionSQL())
+	}
+	return fmt.Sprintf("(\n%s\n\t)", strings.Join(defs, ",\n"))
+}
+
+func (m Partitions) GetPartitionNames() []string {
+	groups := m.GroupByPartitionName()
+	names := make([]string, 0, len(groups))
+	for _, partitions := range groups {
+		names = append(names, partitions[0].PartitionName)
+	}
+	return names
+}
+
+func (m Partitions) getFormatedPartitionNames() string {
+	names := m.GetPartitionNames()
+	for i, name := range names {
+		names[i] = Quote(name)
+	}
+	return strings.Join(names, ",")
+}
+
+func (m Partitions) ToAddSQL() string {
+	return fmt.Sprintf("add partition %s", m.toDefinitionsSQL())
+}
+
+func (m Partitions) ToDropSQL() string {
+	return fmt.Sprintf("drop partition %s", m.getFormatedPartitionNames())
+}
+
+func (m *ReorganizePartition) ToSQL() string {
+	return fmt.Sprintf("reorganize partition %s into %s", m.Partitions.getFormatedPartitionNames(), m.Into.toDefinitionsSQL())
+}
+
+// GroupByPartitionName returns the partitions grouped by the partition name in order,
+// so that each group has the subpartitions of a partition.
+func (m Partitions) GroupByPartitionName() []Partitions {
+	groups := []Partitions{}
+	for _, partition := range m {
+		if n := len(groups); n > 0 && groups[n-1][0].PartitionName == partition.PartitionName {
+			groups[n-1] = append(groups[n-1], partition)
+			continue
+		}
+		groups = append(groups, Partitions{partition})
+	}
+	return groups
 }
 
 func GetPartitions(db *sql.DB, schema string, tableName string) (Partitions, error) {
@@ -74,7 +217,7 @@ func GetPartitions(db *sql.DB, schema string, tableName string) (Partitions, err
 		"NODEGROUP",
 		"TABLESPACE_NAME",
 	}
-	query := fmt.Sprintf(`select %s from information_schema.partitions where TABLE_SCHEMA=%s and TABLE_NAME=%s`, strings.Join(selectCols, ","), QuoteString(schema), QuoteString(tableName))
+	query := fmt.Sprintf(`select %s from information_schema.partitions where TABLE_SCHEMA=%s and TABLE_NAME=%s order by PARTITION_ORDINAL_POSITION,SUBPARTITION_ORDINAL_POSITION`, strings.Join(selectCols, ","), QuoteString(schema), QuoteString(tableName))
 	rows, err = db.Query(query)
 	if err != nil {
 		return nil, fmt.Errorf("err: db.Query `%s' failed for reason %s", query, err)
@@ -0,0 +1,141 @@
+package mysql
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+)
+
+var (
+	versionCommentRegexp = regexp.MustCompile(`/\*!\d+|\*/`)
+	engineOptionRegexp   = regexp.MustCompile(`(?i)\s*ENGINE = \w+`)
+	whitespaceRegexp     = regexp.MustCompile(`\s+`)
+)
+
+// normalizePartitionSQL removes the differences of the format between show create table and Partitions.ToSQL,
+// which are the version comments, the engines, the backticks, the whitespaces and the case of the keywords.
+func normalizePartitionSQL(s string) string {
+	s = versionCommentRegexp.ReplaceAllString(s, "")
+	s = engineOptionRegexp.ReplaceAllString(s, "")
+	s = strings.Replace(s, "`", "", -1)
+	return strings.ToLower(whitespaceRegexp.ReplaceAllString(s, ""))
+}
+
+func makePartitions(method, expression string, descriptions ...string) Partitions {
+	partitions := Partitions{}
+	for i, desc := range descriptions {
+		p := &Partition{TableName: "log", PartitionName: "p" + string(rune('0'+i)), PartitionMethod: method, PartitionExpression: expression}
+		if desc != "" {
+			p.PartitionDescription = nullString(desc)
+		}
+		partitions = append(partitions, p)
+	}
+	return partitions
+}
+
+func makeSubpartitions(partitions Partitions, method, expression string, num int) Partitions {
+	subpartitions := Partitions{}
+	for _, p := range partitions {
+		for i := 0; i < num; i++ {
+			sub := *p
+			sub.SubpartitionMethod = nullString(method)
+			sub.SubpartitionExpression = nullString(expression)
+			sub.SubpartitionName = nullString(p.PartitionName + "sp" + string(rune('0'+i)))
+			subpartitions = append(subpartitions, &sub)
+		}
+	}
+	return subpartitions
+}
+
+func TestPartitionsToSQL(t *testing.T) {
+	tests := []struct {
+		name       string
+		partitions Partitions
+		showCreate string
+	}{
+		{
+			"range",
+			makePartitions(PartitionMethodRangeExpr, "to_days(`created_at`)", "736330", "MAXVALUE"),
+			"/*!50100 PARTITION BY RANGE (to_days(`created_at`))\n" +
+				"(PARTITION p0 VALUES LESS THAN (736330) ENGINE = InnoDB,\n" +
+				" PARTITION p1 VALUES LESS THAN MAXVALUE ENGINE = InnoDB) */",
+		},
+		{
+			"range columns",
+			makePartitions(PartitionMethodRange, "`created_at`,`id`", "'2017-01-01',10", "MAXVALUE,MAXVALUE"),
+			"/*!50500 PARTITION BY RANGE  COLUMNS(created_at,id)\n" +
+				"(PARTITION p0 VALUES LESS THAN ('2017-01-01',10) ENGINE = InnoDB,\n" +
+				" PARTITION p1 VALUES LESS THAN (MAXVALUE,MAXVALUE) ENGINE = InnoDB) */",
+		},
+		{
+			"list",
+			makePartitions(PartitionMethodList, "`region`", "1,2", "3"),
+			"/*!50100 PARTITION BY LIST (`region`)\n" +
+				"(PARTITION p0 VALUES IN (1,2) ENGINE = InnoDB,\n" +
+				" PARTITION p1 VALUES IN (3) ENGINE = InnoDB) */",
+		},
+		{
+			"list columns",
+			makePartitions(PartitionMethodListColumns, "`country`,`region`", "('jp',1),('jp',2)", "('us',1)"),
+			"/*!50500 PARTITION BY LIST  COLUMNS(country,region)\n" +
+				"(PARTITION p0 VALUES IN (('jp',1),('jp',2)) ENGINE = InnoDB,\n" +
+				" PARTITION p1 VALUES IN (('us',1)) ENGINE = InnoDB) */",
+		},
+		{
+			"hash",
+			makePartitions(PartitionMethodHash, "`id`", "", "", "", ""),
+			"/*!50100 PARTITION BY HASH (`id`)\nPARTITIONS 4 */",
+		},
+		{
+			"linear hash",
+			makePartitions(PartitionMethodLinearHash, "`id`", "", ""),
+			"/*!50100 PARTITION BY LINEAR HASH (`id`)\nPARTITIONS 2 */",
+		},
+		{
+			"key",
+			makePartitions(PartitionMethodKey, "`id`", "", "", ""),
+			"/*!50100 PARTITION BY KEY (`id`)\nPARTITIONS 3 */",
+		},
+		{
+			"linear key",
+			makePartitions(PartitionMethodLinearKey, "`id`", "", ""),
+			"/*!50100 PARTITION BY LINEAR KEY (`id`)\nPARTITIONS 2 */",
+		},
+		{
+			"range subpartitioned by hash",
+			makeSubpartitions(makePartitions(PartitionMethodRangeExpr, "year(`created_at`)", "2017", "MAXVALUE"), PartitionMethodHash, "to_days(`created_at`)", 2),
+			"/*!50100 PARTITION BY RANGE (year(`created_at`))\n" +
+				"SUBPARTITION BY HASH (to_days(`created_at`))\n" +
+				"(PARTITION p0 VALUES LESS THAN (2017)\n" +
+				" (SUBPARTITION p0sp0 ENGINE = InnoDB,\n" +
+				"  SUBPARTITION p0sp1 ENGINE = InnoDB),\n" +
+				" PARTITION p1 VALUES LESS THAN MAXVALUE\n" +
+				" (SUBPARTITION p1sp0 ENGINE = InnoDB,\n" +
+				"  SUBPARTITION p1sp1 ENGINE = InnoDB)) */",
+		},
+		{
+			"list subpartitioned by linear key",
+			makeSubpartitions(makePartitions(PartitionMethodList, "`region`", "1,2", "3"), PartitionMethodLinearKey, "`id`", 2),
+			"/*!50100 PARTITION BY LIST (`region`)\n" +
+				"SUBPARTITION BY LINEAR KEY (id)\n" +
+				"(PARTITION p0 VALUES IN (1,2)\n" +
+				" (SUBPARTITION p0sp0 ENGINE = InnoDB,\n" +
+				"  SUBPARTITION p0sp1 ENGINE = InnoDB),\n" +
+				" PARTITION p1 VALUES IN (3)\n" +
+				" (SUBPARTITION p1sp0 ENGINE = InnoDB,\n" +
+				"  SUBPARTITION p1sp1 ENGINE = InnoDB)) */",
+		},
+	}
+	for _, test := range tests {
+		actual := test.partitions.ToSQL()
+		if normalizePartitionSQL(actual) != normalizePartitionSQL(test.showCreate) {
+			t.Errorf("err: unexpected SQL of %s partitioning.\nexpected %s\nbut actual %s", test.name, test.showCreate, actual)
+		}
+	}
+}
+
+func TestPartitionsToSQLWithUnknownMethod(t *testing.T) {
+	if actual := makePartitions("SYSTEM_TIME", "", "").ToSQL(); actual != "" {
+		t.Errorf("err: unexpected SQL %s", actual)
+	}
+}
@@ -0,0 +1,147 @@
+package mysql
+
+import (
+	"database/sql"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+type (
+	// Routine is a stored procedure or function.
+	// The definition is the result of show create procedure or show create function without the definer,
+	// so that the routines are created by the user who builds them.
+	Routine struct {
+		RoutineSchema string
+		RoutineName   string
+		RoutineType   string
+		Definition    string
+	}
+	Routines []*Routine
+)
+
+const (
+	RoutineTypeProcedure = "PROCEDURE"
+	RoutineTypeFunction  = "FUNCTION"
+)
+
+var (
+	dropRoutineSQLFmt string = `drop %s if exists %s`
+	definerRegexp            = regexp.MustCompile("(?i)^(create\\s+)definer\\s*=\\s*(?:`(?:[^`]|``)*`|'(?:[^']|'')*'|[^@\\s]+)(?:@(?:`(?:[^`]|``)*`|'(?:[^']|'')*'|\\S+))?\\s+")
+)
+
+func (m *Routine) GetFormatedRoutineName() string {
+	return Quote(m.RoutineName)
+}
+
+// GetKey returns the type and the name like "PROCEDURE name" because a procedure and a function can have the same name.
+func (m *Routine) GetKey() string {
+	return fmt.Sprintf("%s %s", strings.ToUpper(m.RoutineType), m.RoutineName)
+}
+
+func (m *Routine) ToCreateSQL() string {
+	return m.Definition
+}
+
+func (m *Routine) ToDropSQL() string {
+	return fmt.Sprintf(dropRoutineSQLFmt, strings.ToLower(m.RoutineType), m.GetFormatedRoutineName())
+}
+
+// Equal reports whether both routines have the same definition.
+// Schema names are ignored.
+func (m *Routine) Equal(r *Routine) bool {
+	return m.GetKey() == r.GetKey() && m.Definition == r.Definition
+}
+
+func (m Routines) GroupByKey() map[string]*Routine {
+	keyMap := make(map[string]*Routine, len(m))
+	for _, routine := range m {
+		keyMap[routine.GetKey()] = routine
+	}
+	return keyMap
+}
+
+func (m Routines) GetSortedKeys() []string {
+	keys := make([]string, 0, len(m))
+	for _, routine := range m {
+		keys = append(keys, routine.GetKey())
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// GetRoutines returns the stored procedures and functions of the schema.
+func GetRoutines(db *sql.DB, schema string) (Routines, error) {
+	query := fmt.Sprintf(`select ROUTINE_SCHEMA,ROUTINE_NAME,ROUTINE_TYPE from information_schema.ROUTINES where ROUTINE_SCHEMA=%s`, QuoteString(schema))
+	rows, err := db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("err: db.Query `%s' failed for reason %s", query, err)
+	}
+	defer rows.Close()
+
+	routines := Routines{}
+	for rows.Next() {
+		routine := &Routine{}
+		if err := rows.Scan(&routine.RoutineSchema, &routine.RoutineName, &routine.RoutineType); err != nil {
+			return nil, err
+		}
+		routines = append(routines, routine)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	for _, routine := range routines {
+		definition, err := showCreateRoutine(db, routine)
+		if err != nil {
+			return nil, err
+		}
+		routine.Definition = removeDefiner(definition)
+	}
+	return routines, nil
+}
+
+// showCreateRoutine returns the create statement of the routine.
+// The result has the columns like name, sql_mode, the create statement and the character sets.
+func showCreateRoutine(db *sql.DB, routine *Routine) (string, error) {
+	query := fmt.Sprintf("show create %s %s.%s", strings.ToLower(routine.RoutineType), Quote(routine.RoutineSchema), routine.GetFormatedRoutineName())
+	rows, err := db.Query(query)
+	if err != nil {
+		return "", fmt.Errorf("err: db.Query `%s' failed for reason %s", query, err)
+	}
+	defer rows.Close()
+	cols, err := rows.Columns()
+	if err != nil {
+		return "", err
+	}
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return "", err
+		}
+		return "", fmt.Errorf("err: %s `%s' is not found", strings.ToLower(routine.RoutineType), routine.RoutineName)
+	}
+	holders := make([]sql.NullString, len(cols))
+	holderPtrs := make([]interface{}, len(cols))
+	for i := range holders {
+		holderPtrs[i] = &holders[i]
+	}
+	if err := rows.Scan(holderPtrs...); err != nil {
+		return "", err
+	}
+	for i, col := range cols {
+		if !strings.HasPrefix(strings.ToLower(col), "create ") {
+			continue
+		}
+		if !holders[i].Valid {
+			// the definition is hidden from the user who does not have the privilege
+			return "", fmt.Errorf("err: The definition of %s `%s' is not readable", strings.ToLower(routine.RoutineType), routine.RoutineName)
+		}
+		return holders[i].String, nil
+	}
+	return "", fmt.Errorf("err: `%s' returned no create statement", query)
+}
+
+// removeDefiner removes the definer clause from the create statement like CREATE DEFINER=`root`@`localhost` PROCEDURE ...
+func removeDefiner(createSQL string) string {
+	return definerRegexp.ReplaceAllString(createSQL, "${1}")
+}
@@ -0,0 +1,64 @@
+package mysql
+
+import "testing"
+
+func TestTriggerToCreateSQL(t *testing.T) {
+	trigger := &Trigger{
+		TriggerName:       "user_log",
+		EventManipulation: "INSERT",
+		EventObjectTable:  "user",
+		ActionTiming:      "AFTER",
+		ActionStatement:   "BEGIN\n\tinsert into `log` values (new.`id`);\nEND",
+	}
+	expected := "create trigger `user_log` after insert on `user` for each row BEGIN\n\tinsert into `log` values (new.`id`);\nEND"
+	if actual := trigger.ToCreateSQL(); actual != expected {
+		t.Errorf("err: unexpected SQL.\nexpected %s\nbut actual %s", expected, actual)
+	}
+	if actual := trigger.ToDropSQL(); actual != "drop trigger if exists `user_log`" {
+		t.Errorf("err: unexpected SQL %s", actual)
+	}
+}
+
+func TestRoutineToDropSQL(t *testing.T) {
+	tests := []struct {
+		routine  *Routine
+		expected string
+	}{
+		{&Routine{RoutineName: "purge", RoutineType: RoutineTypeProcedure}, "drop procedure if exists `purge`"},
+		{&Routine{RoutineName: "add`one", RoutineType: RoutineTypeFunction}, "drop function if exists `add``one`"},
+	}
+	for _, test := range tests {
+		if actual := test.routine.ToDropSQL(); actual != test.expected {
+			t.Errorf("err: unexpected SQL.\nexpected %s\nbut actual %s", test.expected, actual)
+		}
+	}
+}
+
+func TestRemoveDefiner(t *testing.T) {
+	tests := []struct {
+		createSQL string
+		expected  string
+	}{
+		{
+			"CREATE DEFINER=`root`@`localhost` PROCEDURE `purge`()\nBEGIN\n\tdelete from `log`;\nEND",
+			"CREATE PROCEDURE `purge`()\nBEGIN\n\tdelete from `log`;\nEND",
+		},
+		{
+			"CREATE DEFINER=`a b``c`@`%` FUNCTION `add_one`(x int) RETURNS int\n    DETERMINISTIC\nreturn x + 1",
+			"CREATE FUNCTION `add_one`(x int) RETURNS int\n    DETERMINISTIC\nreturn x + 1",
+		},
+		{
+			"CREATE DEFINER=CURRENT_USER PROCEDURE `p`() select 1",
+			"CREATE PROCEDURE `p`() select 1",
+		},
+		{
+			"CREATE PROCEDURE `p`() select 'DEFINER=`root`@`%` '",
+			"CREATE PROCEDURE `p`() select 'DEFINER=`root`@`%` '",
+		},
+	}
+	for _, test := range tests {
+		if actual := removeDefiner(test.createSQL); actual != test.expected {
+			t.Errorf("err: unexpected SQL.\nexpected %s\nbut actual %s", test.expected, actual)
+		}
+	}
+}
@@ -0,0 +1,34 @@
+package mysql
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// Schema is the objects written in a JSON file.
+// The file of tables is an array of tables, and the files of the other objects are an object like {"Views": [...]}.
+type Schema struct {
+	Tables   Tables   `json:",omitempty"`
+	Views    Views    `json:",omitempty"`
+	Triggers Triggers `json:",omitempty"`
+	Routines Routines `json:",omitempty"`
+	Events   Events   `json:",omitempty"`
+}
+
+// UnmarshalJSON reads either an array of tables or an object of the objects.
+func (m *Schema) UnmarshalJSON(data []byte) error {
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("[")) {
+		return json.Unmarshal(data, &m.Tables)
+	}
+	type schema Schema
+	return json.Unmarshal(data, (*schema)(m))
+}
+
+// Merge appends the objects of the specified schema.
+func (m *Schema) Merge(s *Schema) {
+	m.Tables = append(m.Tables, s.Tables...)
+	m.Views = append(m.Views, s.Views...)
+	m.Triggers = append(m.Triggers, s.Triggers...)
+	m.Routines = append(m.Routines, s.Routines...)
+	m.Events = append(m.Events, s.Events...)
+}
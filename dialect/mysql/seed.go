@@ -1,10 +1,7 @@
 package mysql
 
 import (
-	"database/sql"
-	"encoding/json"
 	"fmt"
-	"strconv"
 	"strings"
 	"time"
 )
@@ -27,6 +24,7 @@ var (
 	TimeFmt         string = "2006-01-02 15:04:05"
 	defaultBulkSize int    = 2000
 	trancateSQLFmt  string = `truncate table %s`
+	deleteAllSQLFmt string = `delete from %s`
 	insertSQLFmt    string = `insert into %s(%s)
 values
 %s`
@@ -47,6 +45,35 @@ func (m *Chunk) GetColumnIndexBy(columnName string) (int, error) {
 	return 0, fmt.Errorf("err: Specified columnName `%s' is not found in this table %s", columnName, m.TableName)
 }
 
+func (m *Chunk) GetColumnIndicesBy(columnNames []string) ([]int, error) {
+	colIdxs := make([]int, 0, len(columnNames))
+	for _, columnName := range columnNames {
+		colIdx, err := m.GetColumnIndexBy(columnName)
+		if err != nil {
+			return nil, err
+		}
+		colIdxs = append(colIdxs, colIdx)
+	}
+	return colIdxs, nil
+}
+
+// GetSeedGroupByColumns returns a map of the values of the specified columns to the seed.
+// The key is made by ToTupleValue so that the composite key can be compared.
+func (m *Chunk) GetSeedGroupByColumns(columnNames []string) (map[string]Seed, error) {
+	colIdxs, err := m.GetColumnIndicesBy(columnNames)
+	if err != nil {
+		return nil, err
+	}
+	cdMap := make(map[string]Seed, len(m.Seeds))
+	for _, seed := range m.Seeds {
+		if len(seed.ColumnData) <= 0 {
+			continue
+		}
+		cdMap[seed.ToTupleValue(colIdxs...)] = seed
+	}
+	return cdMap, nil
+}
+
 func (m *Chunk) GetSeedGroupBy(columnName string) (map[interface{}]Seed, error) {
 	colIdx, err := m.GetColumnIndexBy(columnName)
 	if err != nil {
@@ -70,6 +97,12 @@ func (m *Chunk) ToTrancateSQL() string {
 	return fmt.Sprintf(trancateSQLFmt, m.GetFormatedTableName())
 }
 
+// ToDeleteAllSQL uses delete statement instead of truncate statement,
+// because truncate statement commits implicitly and can not be rolled back.
+func (m *Chunk) ToDeleteAllSQL() string {
+	return fmt.Sprintf(deleteAllSQLFmt, m.GetFormatedTableName())
+}
+
 func (m *Chunk) ToInsertSQL() []string {
 	columnStr := strings.Join(QuoteMulti(m.ColumnNames), ",")
 
@@ -92,8 +125,17 @@ func (m *Chunk) ToInsertSQL() []string {
 	return queries
 }
 
-func (m *Chunk) ToDeleteSQL(colIdx int) []string {
-	columnStr := Quote(m.ColumnNames[colIdx])
+// ToDeleteSQL deletes the rows which have the values of the specified columns.
+// A composite key is compared as a row constructor like (`a`,`b`) in ((1,2),(3,4)).
+func (m *Chunk) ToDeleteSQL(colIdxs ...int) []string {
+	names := make([]string, 0, len(colIdxs))
+	for _, colIdx := range colIdxs {
+		names = append(names, m.ColumnNames[colIdx])
+	}
+	columnStr := strings.Join(QuoteMulti(names), ",")
+	if len(colIdxs) > 1 {
+		columnStr = fmt.Sprintf("(%s)", columnStr)
+	}
 	seedSize := defaultBulkSize
 	if seedSize > len(m.Seeds) {
 		seedSize = len(m.Seeds)
@@ -103,12 +145,12 @@ func (m *Chunk) ToDeleteSQL(colIdx int) []string {
 	for _, seed := range m.Seeds {
 		seeds = append(seeds, seed)
 		if len(seeds) >= defaultBulkSize {
-			queries = append(queries, fmt.Sprintf(deleteSQLFmt, m.GetFormatedTableName(), columnStr, strings.Join(seeds.ToColumnValues(colIdx), ",\n")))
+			queries = append(queries, fmt.Sprintf(deleteSQLFmt, m.GetFormatedTableName(), columnStr, strings.Join(seeds.ToColumnValues(colIdxs...), ",\n")))
 			seeds = Seeds{}
 		}
 	}
 	if len(seeds) > 0 {
-		queries = append(queries, fmt.Sprintf(deleteSQLFmt, m.GetFormatedTableName(), columnStr, strings.Join(seeds.ToColumnValues(colIdx), ",\n")))
+		queries = append(queries, fmt.Sprintf(deleteSQLFmt, m.GetFormatedTableName(), columnStr, strings.Join(seeds.ToColumnValues(colIdxs...), ",\n")))
 	}
 	return queries
 }
@@ -151,10 +193,10 @@ func (m Seed) ToValueSQL() string {
 	return strings.Join(str, ",")
 }
 
-func (m Seeds) ToColumnValues(colIdx int) []string {
+func (m Seeds) ToColumnValues(colIdxs ...int) []string {
 	values := make([]string, 0, len(m))
 	for _, seed := range m {
-		values = append(values, seed.ToColumnValue(colIdx))
+		values = append(values, seed.ToTupleValue(colIdxs...))
 	}
 	return values
 }
@@ -163,6 +205,18 @@ func (m Seed) ToColumnValue(colIdx int) string {
 	return toString(m.ColumnData[colIdx])
 }
 
+// ToTupleValue returns the value of the column, or the row constructor of the values for several columns.
+func (m Seed) ToTupleValue(colIdxs ...int) string {
+	if len(colIdxs) == 1 {
+		return m.ToColumnValue(colIdxs[0])
+	}
+	values := make([]string, 0, len(colIdxs))
+	for _, colIdx := range colIdxs {
+		values = append(values, m.ToColumnValue(colIdx))
+	}
+	return fmt.Sprintf("(%s)", strings.Join(values, ","))
+}
+
 func (m Seed) ValueEqual(seed Seed) bool {
 	cnt := len(m.ColumnData)
 	for i := 0; i < cnt; i++ {
@@ -181,71 +235,14 @@ func toString(data interface{}) (str string) {
 		str = QuoteString(data.(string))
 	case time.Time:
 		str = QuoteString(data.(time.Time).Format(TimeFmt))
+	case []byte:
+		// binary values are written as hex literals which keep any bytes
+		str = fmt.Sprintf("0x%x", data.([]byte))
+		if len(data.([]byte)) <= 0 {
+			str = "X''"
+		}
 	default:
 		str = fmt.Sprintf("%v", data)
 	}
 	return str
 }
-
-func GetChunk(db *sql.DB, table string, colName *string) (*Chunk, error) {
-	cntCol := "*"
-	if colName != nil {
-		cntCol = Quote(*colName)
-	}
-	res, err := db.Exec(fmt.Sprintf("select count(%s) from %s", cntCol, Quote(table)))
-	if err != nil {
-		return nil, err
-	}
-	cnt, err := res.RowsAffected()
-	if err != nil {
-		return nil, err
-	}
-	rows, err := db.Query(fmt.Sprintf("select * from %s", table))
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	columns, err := rows.Columns()
-	if err != nil {
-		return nil, err
-	}
-	cnk := &Chunk{
-		TableName:   table,
-		ColumnNames: columns,
-		Seeds:       make(Seeds, 0, cnt),
-	}
-	colLen := len(columns)
-	holderPtrs := make([]interface{}, colLen)
-	for rows.Next() {
-		holders := make([]interface{}, colLen)
-		for i := range columns {
-			holderPtrs[i] = &holders[i]
-		}
-		if err := rows.Scan(holderPtrs...); err != nil {
-			return nil, err
-		}
-		for i := range columns {
-			var v interface{}
-			var err error
-			if b, ok := holders[i].([]byte); ok {
-				if v, err = strconv.ParseFloat(string(json.Number(string(b))), 64); err != nil {
-					v = string(b)
-					if v == "0000-00-00 00:00:00" || v == "0000-00-00" {
-						v = ""
-					}
-				} else {
-					if len(string(b)) > 1 && string(string(b)[0]) == "0" {
-						v = string(b)
-					}
-				}
-			} else {
-				v = holders[i]
-			}
-			holders[i] = v
-		}
-		cnk.Seeds = append(cnk.Seeds, Seed{
-			ColumnData: holders,
-		})
-	}
-	return cnk, nil
-}
@@ -9,20 +9,23 @@ import (
 
 type (
 	Table struct {
-		TableCatalog   string
-		TableSchema    string
-		TableName      string
-		TableType      string
-		Engine         string
-		Version        int
-		RowFormat      string
-		TableCollation string
-		CheckSum       JsonNullString
-		CreateOptions  string
-		TableComment   string
-		Columns        Columns
-		Indices        Indices
-		Partitions     Partitions
+		TableCatalog     string
+		TableSchema      string
+		TableName        string
+		TableType        string
+		Engine           string
+		Version          int
+		RowFormat        string
+		TableCollation   string
+		CheckSum         JsonNullString
+		CreateOptions    string
+		TableComment     string
+		Columns          Columns
+		Indices          Indices
+		Partitions       Partitions
+		ForeignKeys      ForeignKeys
+		CheckConstraints CheckConstraints
+		PreviousNames    []string `json:",omitempty"`
 	}
 	Tables []*Table
 )
@@ -31,8 +34,9 @@ var (
 	createSQLFmt string = `create table if not exists %s (
 	%s
 ) engine=%s default charset=%s %s`
-	dropSQLFmt  string = `drop table if exists %s`
-	alterSQLFmt string = `alter table %s %s
+	dropSQLFmt   string = `drop table if exists %s`
+	renameSQLFmt string = `rename table %s to %s`
+	alterSQLFmt  string = `alter table %s %s
 	%s`
 )
 
@@ -51,7 +55,11 @@ func (m *Table) GetCharset() string {
 
 func (m *Table) ToAlterSQL(sqls []string, partitionSql string) string {
 	if len(sqls) <= 0 {
-		return ""
+		if partitionSql == "" {
+			return ""
+		}
+		// the partition options can be specified without other changes
+		return fmt.Sprintf(alterSQLFmt, m.GetFormatedTableName(), partitionSql, "")
 	}
 	return fmt.Sprintf(alterSQLFmt, m.GetFormatedTableName(), strings.Join(sqls, ",\n	"), partitionSql)
 }
@@ -67,9 +75,13 @@ func (m Tables) GetFormatedTableNames() []string {
 func (m *Table) ToCreateSQL() string {
 	columnSQLs := m.Columns.ToSQL()
 	indexSQLs := m.Indices.ToSQL()
+	foreignKeySQLs := m.ForeignKeys.ToSQL()
+	checkSQLs := m.CheckConstraints.ToSQL()
 	partitionSQL := m.Partitions.ToSQL()
-	sqls := make([]string, 0, len(columnSQLs)+len(indexSQLs))
+	sqls := make([]string, 0, len(columnSQLs)+len(indexSQLs)+len(foreignKeySQLs)+len(checkSQLs))
 	sqls = append(columnSQLs, indexSQLs...)
+	sqls = append(sqls, foreignKeySQLs...)
+	sqls = append(sqls, checkSQLs...)
 	return fmt.Sprintf(createSQLFmt, m.GetFormatedTableName(), strings.Join(sqls, ",\n	"), m.Engine, m.GetCharset(), partitionSQL)
 }
 
@@ -77,6 +89,124 @@ func (m *Table) ToDropSQL() string {
 	return fmt.Sprintf(dropSQLFmt, m.GetFormatedTableName())
 }
 
+func (m *Table) ToRenameSQL(name string) string {
+	return fmt.Sprintf(renameSQLFmt, m.GetFormatedTableName(), Quote(name))
+}
+
+func (m *Table) HasPreviousName(name string) bool {
+	for _, n := range m.PreviousNames {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// WithTableName returns a copy of the table which is named as the specified name.
+func (m *Table) WithTableName(name string) *Table {
+	table := m.clone()
+	table.TableName = name
+	for _, column := range table.Columns {
+		column.TableName = name
+	}
+	for _, index := range table.Indices {
+		for i := range index {
+			index[i].Table = name
+		}
+	}
+	for _, partition := range table.Partitions {
+		partition.TableName = name
+	}
+	for _, fk := range table.ForeignKeys {
+		if fk.IsSelfReferenced() {
+			fk.ReferencedTableName = name
+		}
+		fk.TableName = name
+	}
+	for _, c := range table.CheckConstraints {
+		c.TableName = name
+	}
+	return table
+}
+
+// WithRenamedColumns returns a copy of the table whose columns are renamed by the specified old to new name map.
+// The column names in the indices and the foreign keys are also renamed.
+func (m *Table) WithRenamedColumns(names map[string]string) *Table {
+	rename := func(name string) string {
+		if n, ok := names[name]; ok {
+			return n
+		}
+		return name
+	}
+	table := m.clone()
+	for _, column := range table.Columns {
+		column.ColumnName = rename(column.ColumnName)
+	}
+	for _, index := range table.Indices {
+		for i := range index {
+			index[i].ColumnName = rename(index[i].ColumnName)
+		}
+	}
+	for _, fk := range table.ForeignKeys {
+		for i := range fk.ColumnNames {
+			fk.ColumnNames[i] = rename(fk.ColumnNames[i])
+		}
+	}
+	return table
+}
+
+// WithRenamedIndices returns a copy of the table whose indices are renamed by the specified old to new name map.
+func (m *Table) WithRenamedIndices(names map[string]string) *Table {
+	table := m.clone()
+	for i, index := range table.Indices {
+		if n, ok := names[index.GetKeyName()]; ok {
+			table.Indices[i] = index.WithKeyName(n)
+		}
+	}
+	return table
+}
+
+func (m *Table) clone() *Table {
+	table := *m
+	if m.Columns != nil {
+		table.Columns = make(Columns, 0, len(m.Columns))
+		for _, column := range m.Columns {
+			c := *column
+			table.Columns = append(table.Columns, &c)
+		}
+	}
+	if m.Indices != nil {
+		table.Indices = make(Indices, 0, len(m.Indices))
+		for _, index := range m.Indices {
+			table.Indices = append(table.Indices, append(Index{}, index...))
+		}
+	}
+	if m.Partitions != nil {
+		table.Partitions = make(Partitions, 0, len(m.Partitions))
+		for _, partition := range m.Partitions {
+			p := *partition
+			table.Partitions = append(table.Partitions, &p)
+		}
+	}
+	if m.ForeignKeys != nil {
+		table.ForeignKeys = make(ForeignKeys, 0, len(m.ForeignKeys))
+		for _, foreignKey := range m.ForeignKeys {
+			fk := *foreignKey
+			fk.ColumnNames = append([]string{}, foreignKey.ColumnNames...)
+			fk.ReferencedColumnNames = append([]string{}, foreignKey.ReferencedColumnNames...)
+			table.ForeignKeys = append(table.ForeignKeys, &fk)
+		}
+	}
+	if m.CheckConstraints != nil {
+		table.CheckConstraints = make(CheckConstraints, 0, len(m.CheckConstraints))
+		for _, check := range m.CheckConstraints {
+			c := *check
+			table.CheckConstraints = append(table.CheckConstraints, &c)
+		}
+	}
+	return &table
+}
+
 func (m *Table) ToConvertCharsetSQL() string {
 	return fmt.Sprintf("convert to character set %s", m.GetCharset())
 }
@@ -107,6 +237,7 @@ func (m Tables) GetSortedTableNames() []string {
 	return names
 }
 
+// GetTables returns the base tables of the schema. Views are read by GetViews.
 func GetTables(db *sql.DB, schema string, tableNames ...string) (Tables, error) {
 	var rows *sql.Rows
 	var err error
@@ -124,13 +255,13 @@ func GetTables(db *sql.DB, schema string, tableNames ...string) (Tables, error)
 		"CREATE_OPTIONS",
 		"TABLE_COMMENT",
 	}
-	query := fmt.Sprintf(`select %s from information_schema.tables where TABLE_SCHEMA=%s`, strings.Join(selectCols, ","), QuoteString(schema))
+	query := fmt.Sprintf(`select %s from information_schema.tables where TABLE_SCHEMA=%s and TABLE_TYPE='BASE TABLE'`, strings.Join(selectCols, ","), QuoteString(schema))
 	if len(tableNames) > 0 {
 		tn := make([]string, 0, len(tableNames))
 		for _, t := range tableNames {
 			tn = append(tn, QuoteString(t))
 		}
-		query = fmt.Sprintf(`select %s from information_schema.tables where TABLE_SCHEMA=%s and TABLE_NAME in (%s)`, strings.Join(selectCols, ","), QuoteString(schema), strings.Join(tn, ","))
+		query = fmt.Sprintf(`select %s from information_schema.tables where TABLE_SCHEMA=%s and TABLE_TYPE='BASE TABLE' and TABLE_NAME in (%s)`, strings.Join(selectCols, ","), QuoteString(schema), strings.Join(tn, ","))
 	}
 	rows, err = db.Query(query)
 	if err != nil {
@@ -162,6 +293,14 @@ func GetTables(db *sql.DB, schema string, tableNames ...string) (Tables, error)
 	if err != nil {
 		return nil, err
 	}
+	foreignKeys, err := GetForeignKeys(db, schema)
+	if err != nil {
+		return nil, err
+	}
+	checks, err := GetCheckConstraints(db, schema)
+	if err != nil {
+		return nil, err
+	}
 	for i, table := range tables {
 		indices, err := GetIndices(db, table.TableName)
 		if err != nil {
@@ -181,6 +320,26 @@ func GetTables(db *sql.DB, schema string, tableNames ...string) (Tables, error)
 			}
 			tables[i].Partitions = partitions
 		}
+		fks := ForeignKeys{}
+		for _, v := range foreignKeys {
+			if table.TableName != v.TableName {
+				continue
+			}
+			fks = append(fks, v)
+		}
+		if len(fks) > 0 {
+			tables[i].ForeignKeys = fks
+		}
+		cs := CheckConstraints{}
+		for _, v := range checks {
+			if table.TableName != v.TableName {
+				continue
+			}
+			cs = append(cs, v)
+		}
+		if len(cs) > 0 {
+			tables[i].CheckConstraints = cs
+		}
 		tables[i].Columns = c
 		tables[i].Indices = indices
 	}
@@ -0,0 +1,97 @@
+package mysql
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+type (
+	// Trigger is a trigger of information_schema.TRIGGERS.
+	// The definer is not managed so that the triggers are created by the user who builds them.
+	Trigger struct {
+		TriggerSchema     string
+		TriggerName       string
+		EventManipulation string
+		EventObjectTable  string
+		ActionTiming      string
+		ActionStatement   string
+	}
+	Triggers []*Trigger
+)
+
+var (
+	createTriggerSQLFmt string = `create trigger %s %s %s on %s for each row %s`
+	dropTriggerSQLFmt   string = `drop trigger if exists %s`
+)
+
+func (m *Trigger) GetFormatedTriggerName() string {
+	return Quote(m.TriggerName)
+}
+
+func (m *Trigger) ToCreateSQL() string {
+	return fmt.Sprintf(createTriggerSQLFmt, m.GetFormatedTriggerName(), strings.ToLower(m.ActionTiming), strings.ToLower(m.EventManipulation), Quote(m.EventObjectTable), m.ActionStatement)
+}
+
+func (m *Trigger) ToDropSQL() string {
+	return fmt.Sprintf(dropTriggerSQLFmt, m.GetFormatedTriggerName())
+}
+
+// Equal reports whether both triggers generate the same definition.
+// Schema names are ignored.
+func (m *Trigger) Equal(t *Trigger) bool {
+	return m.ToCreateSQL() == t.ToCreateSQL()
+}
+
+func (m Triggers) GroupByTriggerName() map[string]*Trigger {
+	nameMap := make(map[string]*Trigger, len(m))
+	for _, trigger := range m {
+		nameMap[trigger.TriggerName] = trigger
+	}
+	return nameMap
+}
+
+func (m Triggers) GetSortedTriggerNames() []string {
+	names := make([]string, 0, len(m))
+	for _, trigger := range m {
+		names = append(names, trigger.TriggerName)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// GetTriggers returns the triggers of the schema ordered by the tables and the order of execution.
+func GetTriggers(db *sql.DB, schema string) (Triggers, error) {
+	selectCols := []string{
+		"TRIGGER_SCHEMA",
+		"TRIGGER_NAME",
+		"EVENT_MANIPULATION",
+		"EVENT_OBJECT_TABLE",
+		"ACTION_TIMING",
+		"ACTION_STATEMENT",
+	}
+	query := fmt.Sprintf(`select %s from information_schema.TRIGGERS where TRIGGER_SCHEMA=%s order by EVENT_OBJECT_TABLE, ACTION_ORDER`, strings.Join(selectCols, ","), QuoteString(schema))
+	rows, err := db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("err: db.Query `%s' failed for reason %s", query, err)
+	}
+	defer rows.Close()
+
+	triggers := Triggers{}
+	for rows.Next() {
+		trigger := &Trigger{}
+		if err := rows.Scan(
+			&trigger.TriggerSchema,
+			&trigger.TriggerName,
+			&trigger.EventManipulation,
+			&trigger.EventObjectTable,
+			&trigger.ActionTiming,
+			&trigger.ActionStatement,
+		); err != nil {
+			return nil, err
+		}
+		triggers = append(triggers, trigger)
+	}
+	return triggers, rows.Err()
+}
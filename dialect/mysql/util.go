@@ -69,21 +69,36 @@ func (v *JsonNullString) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
+// Quote returns the identifier enclosed in backticks, whose backticks are doubled.
+// Every identifier in the generated SQLs and the queries to read the tables has to be quoted by this.
 func Quote(name string) string {
-	return fmt.Sprintf("`%s`", name)
+	return fmt.Sprintf("`%s`", strings.Replace(name, "`", "``", -1))
 }
 
 func QuoteMulti(names []string) []string {
 	res := make([]string, 0, len(names))
 	for _, name := range names {
-		res = append(res, fmt.Sprintf("`%s`", name))
+		res = append(res, Quote(name))
 	}
 	return res
 }
 
+// QuoteString returns the string literal enclosed in single quotes, which are not identifier quotes even in ANSI_QUOTES mode.
+// The single quotes are doubled, and the special characters are escaped by backslashes,
+// which are always escape characters on the connections made by Dialect.DataSourceName.
 func QuoteString(name string) string {
-	if strings.Contains(name, "\"") {
-		name = strings.Replace(name, "\"", "\"\"", -1)
-	}
-	return fmt.Sprintf("\"%s\"", name)
+	return fmt.Sprintf("'%s'", stringEscaper.Replace(name))
 }
+
+var stringEscaper = strings.NewReplacer(
+	"'", "''",
+	"\\", "\\\\",
+	"\x00", "\\0",
+	"\n", "\\n",
+	"\r", "\\r",
+	"\x1a", "\\Z",
+)
+
+// backslashEscapesSQLMode is the sql_mode of the session without NO_BACKSLASH_ESCAPES,
+// which is set to each connection so that QuoteString does not depend on the sql_mode of the server.
+const backslashEscapesSQLMode = "trim(both ',' from replace(concat(',', @@session.sql_mode, ','), ',NO_BACKSLASH_ESCAPES,', ','))"
@@ -0,0 +1,223 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	_ "github.com/go-sql-driver/mysql"
+)
+
+// unquoteString parses the string literal in the way of MySQL, and fails unless the whole literal is consumed.
+func unquoteString(literal string) (string, error) {
+	if len(literal) < 2 || literal[0] != '\'' {
+		return "", fmt.Errorf("not a string literal %q", literal)
+	}
+	escapes := map[byte]string{'0': "\x00", 'b': "\b", 'n': "\n", 'r': "\r", 't': "\t", 'Z': "\x1a", '%': "\\%", '_': "\\_"}
+	var b strings.Builder
+	for i := 1; i < len(literal); i++ {
+		c := literal[i]
+		switch {
+		case c == '\\':
+			if i+1 >= len(literal) {
+				return "", fmt.Errorf("unterminated escape in %q", literal)
+			}
+			i++
+			if s, ok := escapes[literal[i]]; ok {
+				b.WriteString(s)
+			} else {
+				b.WriteByte(literal[i])
+			}
+		case c == '\'' && i+1 < len(literal) && literal[i+1] == '\'':
+			b.WriteByte('\'')
+			i++
+		case c == '\'':
+			if i != len(literal)-1 {
+				return "", fmt.Errorf("literal %q ends at %d", literal, i)
+			}
+			return b.String(), nil
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return "", fmt.Errorf("unterminated literal %q", literal)
+}
+
+func TestQuoteString(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected string
+	}{
+		{`it's`, `'it''s'`},
+		{`say "hi"`, `'say "hi"'`},
+		{`C:\path\`, `'C:\\path\\'`},
+		{"a\x00b\nc\rd\x1ae", `'a\0b\nc\rd\Ze'`},
+		{`\'); drop table x; --`, `'\\''); drop table x; --'`},
+	}
+	for _, test := range tests {
+		if actual := QuoteString(test.value); actual != test.expected {
+			t.Errorf("err: unexpected literal of %q.\nexpected %s\nbut actual %s", test.value, test.expected, actual)
+		}
+	}
+}
+
+// openTestConn returns a connection to the server with the sql_mode set by Dialect.DataSourceName,
+// after the sql_mode of the session is changed to the specified one to test that it is overridden.
+// It returns nil when the server is not available.
+func openTestConn(tb testing.TB, sqlMode string) *sql.Conn {
+	db, err := sql.Open("mysql", "root@/")
+	if err != nil {
+		tb.Fatal(err)
+	}
+	tb.Cleanup(func() { db.Close() })
+	if err := db.Ping(); err != nil {
+		tb.Logf("mysql server is not available for reason %s", err)
+		return nil
+	}
+	conn, err := db.Conn(context.Background())
+	if err != nil {
+		tb.Fatal(err)
+	}
+	tb.Cleanup(func() { conn.Close() })
+	for _, q := range []string{
+		fmt.Sprintf("set session sql_mode = %s", QuoteString(sqlMode)),
+		fmt.Sprintf("set session sql_mode = %s", backslashEscapesSQLMode),
+	} {
+		if _, err := conn.ExecContext(context.Background(), q); err != nil {
+			tb.Fatal(err)
+		}
+	}
+	return conn
+}
+
+// selectLiteral returns the bytes of the literal read by the server, which are not converted by the character set.
+func selectLiteral(tb testing.TB, conn *sql.Conn, literal string) string {
+	var actual []byte
+	if err := conn.QueryRowContext(context.Background(), "select _binary "+literal).Scan(&actual); err != nil {
+		tb.Fatalf("err: select %s failed for reason %s", literal, err)
+	}
+	return string(actual)
+}
+
+func TestQuoteStringOnServer(t *testing.T) {
+	values := []string{`it's`, `say "hi"`, `C:\path\`, "a\x00b\nc\rd\x1ae", `\'); drop table x; --`, "\xff\xfe", "日本語"}
+	for _, sqlMode := range []string{"", "ANSI_QUOTES", "STRICT_TRANS_TABLES,NO_BACKSLASH_ESCAPES,ANSI_QUOTES", "NO_BACKSLASH_ESCAPES"} {
+		conn := openTestConn(t, sqlMode)
+		if conn == nil {
+			t.Skip("err: mysql server is required")
+		}
+		var mode string
+		if err := conn.QueryRowContext(context.Background(), "select @@session.sql_mode").Scan(&mode); err != nil {
+			t.Fatal(err)
+		}
+		if strings.Contains(mode, "NO_BACKSLASH_ESCAPES") {
+			t.Errorf("err: NO_BACKSLASH_ESCAPES is not removed from sql_mode `%s'", mode)
+		}
+		for _, value := range values {
+			if actual := selectLiteral(t, conn, QuoteString(value)); actual != value {
+				t.Errorf("err: %q is read as %q in sql_mode `%s'", value, actual, sqlMode)
+			}
+		}
+	}
+}
+
+func FuzzQuoteString(f *testing.F) {
+	for _, s := range []string{"", "it's", `C:\`, "\x00\n\r\x1a", `\'`, `''\\''`, "\xff\xfe", "日本語"} {
+		f.Add(s)
+	}
+	// the literals are also read by the server when it is available
+	conn := openTestConn(f, "NO_BACKSLASH_ESCAPES")
+	f.Fuzz(func(t *testing.T, s string) {
+		literal := QuoteString(s)
+		actual, err := unquoteString(literal)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if actual != s {
+			t.Fatalf("err: %q is quoted as %s which means %q", s, literal, actual)
+		}
+		if conn != nil {
+			if actual := selectLiteral(t, conn, literal); actual != s {
+				t.Fatalf("err: %q is quoted as %s which the server reads as %q", s, literal, actual)
+			}
+		}
+	})
+}
+
+func TestQuote(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected string
+	}{
+		{"user", "`user`"},
+		{"order", "`order`"},
+		{"back`tick", "`back``tick`"},
+		{"`", "````"},
+		{"with space", "`with space`"},
+		{"db.table", "`db.table`"},
+	}
+	for _, test := range tests {
+		if actual := Quote(test.name); actual != test.expected {
+			t.Errorf("err: unexpected identifier of %q.\nexpected %s\nbut actual %s", test.name, test.expected, actual)
+		}
+	}
+}
+
+func TestQuoteIdentifiers(t *testing.T) {
+	table := &Table{
+		TableName:      "my`table",
+		Engine:         "InnoDB",
+		TableCollation: "utf8_general_ci",
+		Columns: Columns{
+			{TableName: "my`table", ColumnName: "select", ColumnType: "int(11)", Nullable: "NO"},
+			{TableName: "my`table", ColumnName: "a.b c", ColumnType: "int(11)", Nullable: "NO"},
+		},
+		Indices: Indices{
+			Index{{Table: "my`table", KeyName: "PRIMARY", SeqInIndex: 1, ColumnName: "select"}},
+			Index{{Table: "my`table", NonUniue: 1, KeyName: "key`1", SeqInIndex: 1, ColumnName: "a.b c"}},
+		},
+		ForeignKeys: ForeignKeys{
+			{ConstraintName: "fk`1", ColumnNames: []string{"a.b c"}, ReferencedTableSchema: "my db", ReferencedTableName: "order", ReferencedColumnNames: []string{"id`"}, UpdateRule: "CASCADE", DeleteRule: "CASCADE"},
+		},
+		Partitions: Partitions{
+			{PartitionName: "p`0", PartitionMethod: PartitionMethodRange, PartitionExpression: "`select`", PartitionDescription: JsonNullString{sql.NullString{String: "10", Valid: true}}},
+			{PartitionName: "max value", PartitionMethod: PartitionMethodRange, PartitionExpression: "`select`", PartitionDescription: JsonNullString{sql.NullString{String: "MAXVALUE", Valid: true}}},
+		},
+	}
+	expected := "create table if not exists `my``table` (\n" +
+		"	`select` int(11) not null,\n" +
+		"	`a.b c` int(11) not null,\n" +
+		"	primary key (`select`),\n" +
+		"	key `key``1` (`a.b c`),\n" +
+		"	constraint `fk``1` foreign key (`a.b c`) references `my db`.`order` (`id```) on delete cascade on update cascade\n" +
+		") engine=InnoDB default charset=utf8 partition by range columns (`select`) (\n" +
+		"		partition `p``0` values less than (10),\n" +
+		"		partition `max value` values less than (MAXVALUE)\n" +
+		"	)"
+	if actual := table.ToCreateSQL(); actual != expected {
+		t.Errorf("err: unexpected create SQL.\nexpected %s\nbut actual %s", expected, actual)
+	}
+	if expected, actual := "rename table `my``table` to `new table`", table.ToRenameSQL("new table"); actual != expected {
+		t.Errorf("err: unexpected rename SQL.\nexpected %s\nbut actual %s", expected, actual)
+	}
+
+	cnk := &Chunk{
+		TableName:   "my`table",
+		ColumnNames: []string{"select", "a.b c"},
+		Seeds:       Seeds{{ColumnData: []interface{}{int64(1), int64(2)}}},
+	}
+	queries := append(cnk.ToInsertSQL(), cnk.ToDeleteSQL(0, 1)...)
+	queries = append(queries, cnk.ToTrancateSQL(), cnk.ToDeleteAllSQL())
+	expectedQueries := []string{
+		"insert into `my``table`(`select`,`a.b c`)\nvalues\n(1,2)",
+		"delete from `my``table` where (`select`,`a.b c`) in (\n(1,2)\n)",
+		"truncate table `my``table`",
+		"delete from `my``table`",
+	}
+	if !reflect.DeepEqual(queries, expectedQueries) {
+		t.Errorf("err: unexpected seed SQL.\nexpected %q\nbut actual %q", expectedQueries, queries)
+	}
+}
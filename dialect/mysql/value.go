@@ -0,0 +1,217 @@
+package mysql
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ValueKind is the kind of the values of a column, which decides how the values are converted.
+type ValueKind int
+
+const (
+	StringKind ValueKind = iota
+	IntKind
+	UintKind
+	FloatKind
+	DecimalKind
+	BoolKind
+	DateKind
+	DatetimeKind
+	BinaryKind
+)
+
+// Decimal is the exact representation of a fixed-point number.
+// It is written in SQL without quotes unlike string.
+type Decimal string
+
+var (
+	DateFmt     string = "2006-01-02"
+	DatetimeFmt string = "2006-01-02 15:04:05.999999999"
+	// the zero dates of MySQL are not valid as time.Time
+	zeroDates = map[string]struct{}{
+		"0000-00-00":          {},
+		"0000-00-00 00:00:00": {},
+	}
+	datetimeLayouts = []string{
+		"2006-01-02 15:04:05.999999999",
+		"2006-01-02T15:04:05.999999999",
+		"2006-01-02T15:04:05.999999999Z07:00",
+		"2006-01-02",
+	}
+	binaryHexPrefix    = "0x"
+	binaryBase64Prefix = "base64:"
+	decimalRegexp      = regexp.MustCompile(`^([+-]?)(\d*)(?:\.(\d*))?$`)
+)
+
+// ValueKind returns the kind of the values by the data type of the column.
+// The data types of MySQL, PostgreSQL and SQLite are supported.
+func (m *Column) ValueKind() ValueKind {
+	dataType := strings.ToLower(m.DataType)
+	switch dataType {
+	case "tinyint", "smallint", "mediumint", "int", "integer", "bigint", "year", "int2", "int4", "int8":
+		if strings.Contains(strings.ToLower(m.ColumnType), "unsigned") {
+			return UintKind
+		}
+		return IntKind
+	case "float", "double", "double precision", "real", "float4", "float8":
+		return FloatKind
+	case "decimal", "numeric", "dec":
+		return DecimalKind
+	case "boolean", "bool":
+		return BoolKind
+	case "date":
+		return DateKind
+	case "datetime":
+		return DatetimeKind
+	case "binary", "varbinary", "tinyblob", "blob", "mediumblob", "longblob", "bytea":
+		return BinaryKind
+	}
+	if strings.HasPrefix(dataType, "timestamp") {
+		return DatetimeKind
+	}
+	return StringKind
+}
+
+// ParseValue converts the text of a CSV field to the value of the column.
+// Binary values are decoded as []byte from the hex like 0x0102 or the base64 like base64:AQI=,
+// and the others are taken as they are.
+func (m *Column) ParseValue(s string) (interface{}, error) {
+	if m.ValueKind() == BinaryKind {
+		b, err := DecodeBinary(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s value `%s' for reason %s", m.DataType, s, err)
+		}
+		return b, nil
+	}
+	return m.parseValue(s)
+}
+
+// ConvertValue converts the value scanned from the database to the value of the column.
+// The value which does not match the type is kept as text because some databases like SQLite do not force the types.
+func (m *Column) ConvertValue(v interface{}) interface{} {
+	var s string
+	switch t := v.(type) {
+	case nil:
+		return nil
+	case []byte:
+		if m.ValueKind() == BinaryKind {
+			b := make([]byte, len(t))
+			copy(b, t)
+			return b
+		}
+		s = string(t)
+	case string:
+		if m.ValueKind() == BinaryKind {
+			return []byte(t)
+		}
+		s = t
+	case time.Time:
+		if m.ValueKind() == DateKind {
+			return t.Format(DateFmt)
+		}
+		return t.Format(DatetimeFmt)
+	case bool:
+		if m.ValueKind() == BoolKind {
+			return t
+		}
+		s = "0"
+		if t {
+			s = "1"
+		}
+	case int64:
+		s = strconv.FormatInt(t, 10)
+	case float64:
+		s = strconv.FormatFloat(t, 'f', -1, 64)
+	default:
+		return v
+	}
+	value, err := m.parseValue(s)
+	if err != nil {
+		return s
+	}
+	return value
+}
+
+func (m *Column) parseValue(s string) (interface{}, error) {
+	var value interface{}
+	var err error
+	switch m.ValueKind() {
+	case IntKind:
+		value, err = strconv.ParseInt(s, 10, 64)
+	case UintKind:
+		value, err = strconv.ParseUint(s, 10, 64)
+	case FloatKind:
+		value, err = strconv.ParseFloat(s, 64)
+	case DecimalKind:
+		value, err = parseDecimal(s)
+	case BoolKind:
+		value, err = strconv.ParseBool(s)
+	case DateKind:
+		value, err = parseTime(s, DateFmt, []string{DateFmt})
+	case DatetimeKind:
+		value, err = parseTime(s, DatetimeFmt, datetimeLayouts)
+	default:
+		value = s
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s value `%s'", m.DataType, s)
+	}
+	return value, nil
+}
+
+// parseDecimal returns the decimal without the redundant zeros so that the same numbers are compared as equal.
+func parseDecimal(s string) (Decimal, error) {
+	matches := decimalRegexp.FindStringSubmatch(s)
+	if matches == nil || matches[2]+matches[3] == "" {
+		return "", fmt.Errorf("invalid decimal `%s'", s)
+	}
+	sign, integer, fraction := matches[1], strings.TrimLeft(matches[2], "0"), strings.TrimRight(matches[3], "0")
+	if integer == "" {
+		integer = "0"
+	}
+	if sign == "+" || (integer == "0" && fraction == "") {
+		sign = ""
+	}
+	if fraction == "" {
+		return Decimal(sign + integer), nil
+	}
+	return Decimal(fmt.Sprintf("%s%s.%s", sign, integer, fraction)), nil
+}
+
+func parseTime(s, format string, layouts []string) (string, error) {
+	if _, ok := zeroDates[s]; ok {
+		return s, nil
+	}
+	for _, layout := range layouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t.Format(format), nil
+		}
+	}
+	return "", fmt.Errorf("invalid time `%s'", s)
+}
+
+// EncodeBinary returns the hex like 0x0102, or the base64 like base64:AQI= when encoding is "base64".
+// The prefix marks the encoding so that DecodeBinary can restore the bytes.
+func EncodeBinary(b []byte, encoding string) string {
+	if encoding == "base64" {
+		return binaryBase64Prefix + base64.StdEncoding.EncodeToString(b)
+	}
+	return binaryHexPrefix + hex.EncodeToString(b)
+}
+
+// DecodeBinary decodes the text encoded by EncodeBinary.
+// The text without the prefix is rejected, because the raw bytes can not be distinguished from the encoded ones.
+func DecodeBinary(s string) ([]byte, error) {
+	switch {
+	case strings.HasPrefix(s, binaryHexPrefix):
+		return hex.DecodeString(s[len(binaryHexPrefix):])
+	case strings.HasPrefix(s, binaryBase64Prefix):
+		return base64.StdEncoding.DecodeString(s[len(binaryBase64Prefix):])
+	}
+	return nil, fmt.Errorf("the prefix %s or %s is missing", binaryHexPrefix, binaryBase64Prefix)
+}
@@ -0,0 +1,115 @@
+package mysql
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseValue(t *testing.T) {
+	tests := []struct {
+		dataType   string
+		columnType string
+		value      string
+		expected   interface{}
+	}{
+		{"bigint", "bigint(20)", "9007199254740993", int64(9007199254740993)},
+		{"bigint", "bigint(20) unsigned", "18446744073709551615", uint64(18446744073709551615)},
+		{"int", "int(11)", "-1", int64(-1)},
+		{"double", "double", "1e3", float64(1000)},
+		{"decimal", "decimal(10,3)", "001.500", Decimal("1.5")},
+		{"decimal", "decimal(10,3)", "-0.000", Decimal("0")},
+		{"numeric", "numeric(10,3)", "+.25", Decimal("0.25")},
+		{"boolean", "boolean", "t", true},
+		{"date", "date", "2017-01-02", "2017-01-02"},
+		{"datetime", "datetime", "2017-01-02T03:04:05", "2017-01-02 03:04:05"},
+		{"timestamp without time zone", "timestamp without time zone", "2017-01-02 03:04:05.100", "2017-01-02 03:04:05.1"},
+		{"datetime", "datetime", "0000-00-00 00:00:00", "0000-00-00 00:00:00"},
+		{"varchar", "varchar(32)", "1e3", "1e3"},
+		{"text", "text", "null", "null"},
+		{"varchar", "varchar(32)", "007", "007"},
+		{"varbinary", "varbinary(8)", "0x00ff", []byte{0x00, 0xff}},
+		{"blob", "blob", "base64:AP8=", []byte{0x00, 0xff}},
+		{"bytea", "bytea", "0x74657874", []byte("text")},
+	}
+	for _, test := range tests {
+		column := &Column{DataType: test.dataType, ColumnType: test.columnType}
+		actual, err := column.ParseValue(test.value)
+		if err != nil {
+			t.Errorf("err: ParseValue `%s' for %s failed for reason %s", test.value, test.columnType, err)
+			continue
+		}
+		if !reflect.DeepEqual(actual, test.expected) {
+			t.Errorf("err: unexpected value of `%s' for %s.\nexpected %#v\nbut actual %#v", test.value, test.columnType, test.expected, actual)
+		}
+	}
+}
+
+func TestParseInvalidValue(t *testing.T) {
+	tests := []struct {
+		dataType string
+		value    string
+	}{
+		{"int", "1e3"},
+		{"int", "1.5"},
+		{"bigint", "99999999999999999999"},
+		{"decimal", "1.2.3"},
+		{"decimal", "."},
+		{"date", "2017-02-30"},
+		{"datetime", "yesterday"},
+		{"boolean", "yes"},
+		{"varbinary", "0xzz"},
+		{"bytea", "text"},
+		{"blob", "0X00"},
+	}
+	for _, test := range tests {
+		column := &Column{DataType: test.dataType, ColumnType: test.dataType}
+		if v, err := column.ParseValue(test.value); err == nil {
+			t.Errorf("err: ParseValue `%s' for %s must fail but returns %#v", test.value, test.dataType, v)
+		}
+	}
+}
+
+func TestConvertValue(t *testing.T) {
+	tests := []struct {
+		dataType string
+		value    interface{}
+		expected interface{}
+	}{
+		{"bigint", []byte("9007199254740993"), int64(9007199254740993)},
+		{"integer", int64(3), int64(3)},
+		{"integer", float64(3), int64(3)},
+		{"decimal", []byte("1.500"), Decimal("1.5")},
+		{"double", []byte("1.5"), float64(1.5)},
+		{"boolean", int64(1), true},
+		{"date", time.Date(2017, 1, 2, 0, 0, 0, 0, time.UTC), "2017-01-02"},
+		{"datetime", time.Date(2017, 1, 2, 3, 4, 5, 0, time.UTC), "2017-01-02 03:04:05"},
+		{"varchar", []byte("007"), "007"},
+		{"varbinary", []byte{0x00, 0xff}, []byte{0x00, 0xff}},
+		{"blob", "0x00", []byte("0x00")},
+		// the value which does not match the type is kept as text
+		{"integer", "abc", "abc"},
+		{"integer", nil, nil},
+	}
+	for _, test := range tests {
+		column := &Column{DataType: test.dataType, ColumnType: test.dataType}
+		if actual := column.ConvertValue(test.value); !reflect.DeepEqual(actual, test.expected) {
+			t.Errorf("err: unexpected value of %#v for %s.\nexpected %#v\nbut actual %#v", test.value, test.dataType, test.expected, actual)
+		}
+	}
+}
+
+func TestBinaryLiteral(t *testing.T) {
+	cnk := &Chunk{
+		TableName:   "binary",
+		ColumnNames: []string{"id", "data"},
+		Seeds: Seeds{
+			{ColumnData: []interface{}{int64(1), []byte{0x00, 0xff, '"', '\\'}}},
+			{ColumnData: []interface{}{int64(2), []byte{}}},
+		},
+	}
+	expected := []string{"insert into `binary`(`id`,`data`)\nvalues\n(1,0x00ff225c),\n(2,X'')"}
+	if actual := cnk.ToInsertSQL(); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("err: unexpected SQL.\nexpected %q\nbut actual %q", expected, actual)
+	}
+}
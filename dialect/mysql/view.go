@@ -0,0 +1,149 @@
+package mysql
+
+import (
+	"database/sql"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+type (
+	// View is a view of information_schema.VIEWS.
+	// The definer is not managed so that the views are created by the user who builds them.
+	View struct {
+		TableSchema    string
+		TableName      string
+		ViewDefinition string
+		CheckOption    string
+		SecurityType   string
+		Algorithm      string
+	}
+	Views []*View
+)
+
+var (
+	createViewSQLFmt string = `create or replace algorithm=%s sql security %s view %s as %s`
+	dropViewSQLFmt   string = `drop view if exists %s`
+	algorithmRegexp         = regexp.MustCompile("(?i)^create\\s+algorithm=(\\w+)")
+)
+
+func (m *View) GetFormatedTableName() string {
+	return Quote(m.TableName)
+}
+
+func (m *View) ToCreateSQL() string {
+	algorithm := m.Algorithm
+	if algorithm == "" {
+		algorithm = "UNDEFINED"
+	}
+	securityType := m.SecurityType
+	if securityType == "" {
+		securityType = "DEFINER"
+	}
+	query := fmt.Sprintf(createViewSQLFmt, strings.ToLower(algorithm), strings.ToLower(securityType), m.GetFormatedTableName(), m.ViewDefinition)
+	if m.CheckOption != "" && !strings.EqualFold(m.CheckOption, "NONE") {
+		query = fmt.Sprintf("%s with %s check option", query, strings.ToLower(m.CheckOption))
+	}
+	return query
+}
+
+func (m *View) ToDropSQL() string {
+	return fmt.Sprintf(dropViewSQLFmt, m.GetFormatedTableName())
+}
+
+// Equal reports whether both views generate the same definition.
+// Schema names are ignored.
+func (m *View) Equal(v *View) bool {
+	return m.TableName == v.TableName && m.ToCreateSQL() == v.ToCreateSQL()
+}
+
+// DependsOn reports whether the definition refers to the table or view of the specified name.
+// The definitions read from the server have every table name quoted by backticks.
+func (m *View) DependsOn(name string) bool {
+	return name != m.TableName && strings.Contains(m.ViewDefinition, Quote(name))
+}
+
+func (m Views) GroupByTableName() map[string]*View {
+	nameMap := make(map[string]*View, len(m))
+	for _, view := range m {
+		nameMap[view.TableName] = view
+	}
+	return nameMap
+}
+
+func (m Views) GetSortedTableNames() []string {
+	names := make([]string, 0, len(m))
+	for _, view := range m {
+		names = append(names, view.TableName)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// GetViews returns the views of the schema.
+// The definitions and the algorithms are read by show create view,
+// which omits the schema name of the tables in the same schema unlike information_schema.VIEWS.
+func GetViews(db *sql.DB, schema string) (Views, error) {
+	selectCols := []string{
+		"TABLE_SCHEMA",
+		"TABLE_NAME",
+		"CHECK_OPTION",
+		"SECURITY_TYPE",
+	}
+	query := fmt.Sprintf(`select %s from information_schema.VIEWS where TABLE_SCHEMA=%s`, strings.Join(selectCols, ","), QuoteString(schema))
+	rows, err := db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("err: db.Query `%s' failed for reason %s", query, err)
+	}
+	defer rows.Close()
+
+	views := Views{}
+	for rows.Next() {
+		view := &View{}
+		if err := rows.Scan(
+			&view.TableSchema,
+			&view.TableName,
+			&view.CheckOption,
+			&view.SecurityType,
+		); err != nil {
+			return nil, err
+		}
+		views = append(views, view)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	for _, view := range views {
+		query := fmt.Sprintf("show create view %s.%s", Quote(view.TableSchema), view.GetFormatedTableName())
+		var name, createSQL, charset, collation string
+		if err := db.QueryRow(query).Scan(&name, &createSQL, &charset, &collation); err != nil {
+			return nil, fmt.Errorf("err: db.QueryRow `%s' failed for reason %s", query, err)
+		}
+		if err := view.parseCreateSQL(createSQL); err != nil {
+			return nil, err
+		}
+	}
+	return views, nil
+}
+
+// parseCreateSQL sets the algorithm and the definition from the result of show create view like
+// CREATE ALGORITHM=UNDEFINED DEFINER=`root`@`localhost` SQL SECURITY DEFINER VIEW `v` AS select ... WITH CASCADED CHECK OPTION
+func (m *View) parseCreateSQL(createSQL string) error {
+	matches := algorithmRegexp.FindStringSubmatch(createSQL)
+	if matches == nil {
+		return fmt.Errorf("err: Algorithm of view `%s' is not found in `%s'", m.TableName, createSQL)
+	}
+	m.Algorithm = strings.ToUpper(matches[1])
+	token := fmt.Sprintf(" VIEW %s AS ", m.GetFormatedTableName())
+	pos := strings.Index(createSQL, token)
+	if pos < 0 {
+		return fmt.Errorf("err: Definition of view `%s' is not found in `%s'", m.TableName, createSQL)
+	}
+	definition := createSQL[pos+len(token):]
+	if m.CheckOption != "" && !strings.EqualFold(m.CheckOption, "NONE") {
+		definition = strings.TrimSuffix(definition, fmt.Sprintf(" WITH %s CHECK OPTION", strings.ToUpper(m.CheckOption)))
+	}
+	m.ViewDefinition = definition
+	return nil
+}
@@ -0,0 +1,90 @@
+package mysql
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestViewToCreateSQL(t *testing.T) {
+	tests := []struct {
+		view     *View
+		expected string
+	}{
+		{
+			&View{TableName: "active_user", ViewDefinition: "select `user`.`id` AS `id` from `user` where (`user`.`active` = 1)", CheckOption: "NONE", SecurityType: "DEFINER", Algorithm: "UNDEFINED"},
+			"create or replace algorithm=undefined sql security definer view `active_user` as select `user`.`id` AS `id` from `user` where (`user`.`active` = 1)",
+		},
+		{
+			&View{TableName: "item`s", ViewDefinition: "select 1 AS `1`", CheckOption: "CASCADED", SecurityType: "INVOKER", Algorithm: "MERGE"},
+			"create or replace algorithm=merge sql security invoker view `item``s` as select 1 AS `1` with cascaded check option",
+		},
+		{
+			&View{TableName: "v", ViewDefinition: "select 1 AS `1`"},
+			"create or replace algorithm=undefined sql security definer view `v` as select 1 AS `1`",
+		},
+	}
+	for _, test := range tests {
+		if actual := test.view.ToCreateSQL(); actual != test.expected {
+			t.Errorf("err: unexpected SQL.\nexpected %s\nbut actual %s", test.expected, actual)
+		}
+	}
+}
+
+func TestViewParseCreateSQL(t *testing.T) {
+	tests := []struct {
+		view      *View
+		createSQL string
+		expected  View
+	}{
+		{
+			&View{TableName: "active_user", CheckOption: "NONE"},
+			"CREATE ALGORITHM=UNDEFINED DEFINER=`root`@`localhost` SQL SECURITY DEFINER VIEW `active_user` AS select `user`.`id` AS `id` from `user`",
+			View{TableName: "active_user", CheckOption: "NONE", Algorithm: "UNDEFINED", ViewDefinition: "select `user`.`id` AS `id` from `user`"},
+		},
+		{
+			&View{TableName: "local_user", CheckOption: "LOCAL"},
+			"CREATE ALGORITHM=MERGE DEFINER=`local_user`@`%` SQL SECURITY INVOKER VIEW `local_user` AS select `active_user`.`id` AS `id` from `active_user` WITH LOCAL CHECK OPTION",
+			View{TableName: "local_user", CheckOption: "LOCAL", Algorithm: "MERGE", ViewDefinition: "select `active_user`.`id` AS `id` from `active_user`"},
+		},
+	}
+	for _, test := range tests {
+		if err := test.view.parseCreateSQL(test.createSQL); err != nil {
+			t.Fatal(err)
+		}
+		if *test.view != test.expected {
+			t.Errorf("err: unexpected view parsed from %s.\nexpected %+v\nbut actual %+v", test.createSQL, test.expected, *test.view)
+		}
+	}
+	if err := (&View{TableName: "v"}).parseCreateSQL("CREATE TABLE `v` (`id` int)"); err == nil {
+		t.Error("err: parseCreateSQL accepted the SQL which is not of a view")
+	}
+}
+
+func TestViewEqual(t *testing.T) {
+	v1 := &View{TableSchema: "prod", TableName: "v", ViewDefinition: "select 1 AS `1`", CheckOption: "NONE", SecurityType: "DEFINER", Algorithm: "UNDEFINED"}
+	v2 := &View{TableSchema: "test", TableName: "v", ViewDefinition: "select 1 AS `1`", CheckOption: "NONE", SecurityType: "DEFINER", Algorithm: "UNDEFINED"}
+	if !v1.Equal(v2) {
+		t.Error("err: views in different schemas are not equal")
+	}
+	v2.Algorithm = "TEMPTABLE"
+	if v1.Equal(v2) {
+		t.Error("err: views of different algorithms are equal")
+	}
+}
+
+func TestSchemaUnmarshalJSON(t *testing.T) {
+	s := &Schema{}
+	if err := json.Unmarshal([]byte(` [{"TableName": "user"}]`), s); err != nil {
+		t.Fatal(err)
+	}
+	if len(s.Tables) != 1 || s.Tables[0].TableName != "user" || len(s.Views) != 0 {
+		t.Errorf("err: unexpected schema of array %+v", s)
+	}
+	s = &Schema{}
+	if err := json.Unmarshal([]byte(`{"Views": [{"TableName": "active_user"}]}`), s); err != nil {
+		t.Fatal(err)
+	}
+	if len(s.Views) != 1 || s.Views[0].TableName != "active_user" || len(s.Tables) != 0 {
+		t.Errorf("err: unexpected schema of object %+v", s)
+	}
+}
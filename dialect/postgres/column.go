@@ -0,0 +1,145 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/dev-cloverlab/carpenter/dialect/mysql"
+)
+
+var serialTypes = map[string]string{
+	"smallint": "smallserial",
+	"integer":  "serial",
+	"bigint":   "bigserial",
+}
+
+func columnToSQL(column *mysql.Column) string {
+	token := []string{Quote(column.ColumnName), column.ColumnType}
+	if !column.IsNullable() {
+		token = append(token, "not null")
+	}
+	if column.HasDefault() {
+		token = append(token, "default", formatDefault(column))
+	}
+	if column.HasExtra() && column.Extra.String != "" {
+		token = append(token, column.Extra.String)
+	}
+	return strings.Join(token, " ")
+}
+
+// columnToModifySQL renders the type of serial columns as the integer type,
+// because serial is not a real type, and keeps their default which is the implicit sequence.
+func columnToModifySQL(column *mysql.Column) []string {
+	name := Quote(column.ColumnName)
+	columnType, isSerial := toIntegerType(column.ColumnType)
+	sqls := []string{fmt.Sprintf("alter column %s type %s", name, columnType)}
+	if column.IsNullable() {
+		sqls = append(sqls, fmt.Sprintf("alter column %s drop not null", name))
+	} else {
+		sqls = append(sqls, fmt.Sprintf("alter column %s set not null", name))
+	}
+	if isSerial {
+		return sqls
+	}
+	if column.HasDefault() {
+		sqls = append(sqls, fmt.Sprintf("alter column %s set default %s", name, formatDefault(column)))
+	} else {
+		sqls = append(sqls, fmt.Sprintf("alter column %s drop default", name))
+	}
+	return sqls
+}
+
+// toIntegerType returns the integer type of the serial type and true, or the type as it is and false.
+func toIntegerType(columnType string) (string, bool) {
+	for integerType, serial := range serialTypes {
+		if columnType == serial {
+			return integerType, true
+		}
+	}
+	return columnType, false
+}
+
+func columnToCommentSQL(table *mysql.Table, column *mysql.Column) string {
+	return fmt.Sprintf(commentSQLFmt, "column", fmt.Sprintf("%s.%s", Quote(table.TableName), Quote(column.ColumnName)), QuoteString(column.ColumnComment))
+}
+
+// formatDefault returns the default value as it is, because PostgreSQL reports it as an expression.
+func formatDefault(column *mysql.Column) string {
+	if column.ColumnDefault.String == "" {
+		return QuoteString("")
+	}
+	return column.ColumnDefault.String
+}
+
+func GetColumns(db *sql.DB) (mysql.Columns, error) {
+	selectCols := []string{
+		"c.table_catalog",
+		"c.table_schema",
+		"c.table_name",
+		"c.column_name",
+		"c.ordinal_position",
+		"c.column_default",
+		"c.is_nullable",
+		"c.data_type",
+		"c.character_maximum_length",
+		"c.character_octet_length",
+		"c.numeric_precision",
+		"c.numeric_scale",
+		"c.character_set_name",
+		"c.collation_name",
+		"pg_catalog.format_type(a.atttypid, a.atttypmod)",
+		"c.is_identity",
+		"coalesce(c.identity_generation, '')",
+		"coalesce(pg_catalog.col_description(a.attrelid, a.attnum), '')",
+	}
+	query := fmt.Sprintf(`select %s from information_schema.columns c
+	inner join pg_catalog.pg_attribute a
+		on a.attrelid=(quote_ident(c.table_schema)||'.'||quote_ident(c.table_name))::regclass and a.attname=c.column_name
+	where c.table_schema=current_schema()
+	order by c.table_name, c.ordinal_position`, strings.Join(selectCols, ","))
+	rows, err := db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("err: db.Query `%s' failed for reason %s", query, err)
+	}
+	defer rows.Close()
+
+	columns := mysql.Columns{}
+	for rows.Next() {
+		var isIdentity, identityGeneration string
+		column := &mysql.Column{}
+		if err := rows.Scan(
+			&column.TableCatalog,
+			&column.TableSchema,
+			&column.TableName,
+			&column.ColumnName,
+			&column.OrdinalPosition,
+			&column.ColumnDefault,
+			&column.Nullable,
+			&column.DataType,
+			&column.CharacterMaximumLength,
+			&column.CharacterOctetLength,
+			&column.NumericPrecision,
+			&column.NumericScale,
+			&column.CharacterSetName,
+			&column.CollationName,
+			&column.ColumnType,
+			&isIdentity,
+			&identityGeneration,
+			&column.ColumnComment,
+		); err != nil {
+			return nil, err
+		}
+		// serial columns are managed by the implicit sequence
+		if serial, ok := serialTypes[column.ColumnType]; ok && strings.HasPrefix(column.ColumnDefault.String, "nextval(") {
+			column.ColumnType = serial
+			column.ColumnDefault = mysql.JsonNullString{}
+		}
+		if isIdentity == "YES" {
+			column.Extra.Valid = true
+			column.Extra.String = fmt.Sprintf("generated %s as identity", strings.ToLower(identityGeneration))
+		}
+		columns = append(columns, column)
+	}
+	return columns, nil
+}
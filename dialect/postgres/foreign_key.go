@@ -0,0 +1,86 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/dev-cloverlab/carpenter/dialect/mysql"
+)
+
+func foreignKeyToSQL(fk *mysql.ForeignKey) string {
+	referenced := Quote(fk.ReferencedTableName)
+	if fk.IsExternalReferenced() {
+		referenced = fmt.Sprintf("%s.%s", Quote(fk.ReferencedTableSchema), Quote(fk.ReferencedTableName))
+	}
+	token := []string{
+		"constraint", Quote(fk.ConstraintName),
+		fmt.Sprintf("foreign key (%s)", strings.Join(QuoteMulti(fk.ColumnNames), ",")),
+		fmt.Sprintf("references %s (%s)", referenced, strings.Join(QuoteMulti(fk.ReferencedColumnNames), ",")),
+	}
+	if fk.DeleteRule != "" {
+		token = append(token, "on delete", strings.ToLower(fk.DeleteRule))
+	}
+	if fk.UpdateRule != "" {
+		token = append(token, "on update", strings.ToLower(fk.UpdateRule))
+	}
+	return strings.Join(token, " ")
+}
+
+func GetForeignKeys(db *sql.DB) (mysql.ForeignKeys, error) {
+	rule := "case %s when 'r' then 'RESTRICT' when 'c' then 'CASCADE' when 'n' then 'SET NULL' when 'd' then 'SET DEFAULT' else 'NO ACTION' end"
+	selectCols := []string{
+		"con.conname",
+		"n.nspname",
+		"cl.relname",
+		"a.attname",
+		"rn.nspname",
+		"rcl.relname",
+		"ra.attname",
+		fmt.Sprintf(rule, "con.confupdtype"),
+		fmt.Sprintf(rule, "con.confdeltype"),
+	}
+	query := fmt.Sprintf(`select %s from pg_catalog.pg_constraint con
+	inner join pg_catalog.pg_class cl on cl.oid=con.conrelid
+	inner join pg_catalog.pg_namespace n on n.oid=cl.relnamespace
+	inner join pg_catalog.pg_class rcl on rcl.oid=con.confrelid
+	inner join pg_catalog.pg_namespace rn on rn.oid=rcl.relnamespace
+	cross join lateral unnest(con.conkey, con.confkey) with ordinality as k(attnum, fattnum, n)
+	inner join pg_catalog.pg_attribute a on a.attrelid=con.conrelid and a.attnum=k.attnum
+	inner join pg_catalog.pg_attribute ra on ra.attrelid=con.confrelid and ra.attnum=k.fattnum
+	where con.contype='f' and n.nspname=current_schema()
+	order by cl.relname, con.conname, k.n`, strings.Join(selectCols, ","))
+	rows, err := db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("err: db.Query `%s' failed for reason %s", query, err)
+	}
+	defer rows.Close()
+
+	fks := mysql.ForeignKeys{}
+	fkMap := map[string]*mysql.ForeignKey{}
+	for rows.Next() {
+		var columnName, referencedColumnName string
+		fk := &mysql.ForeignKey{}
+		if err := rows.Scan(
+			&fk.ConstraintName,
+			&fk.TableSchema,
+			&fk.TableName,
+			&columnName,
+			&fk.ReferencedTableSchema,
+			&fk.ReferencedTableName,
+			&referencedColumnName,
+			&fk.UpdateRule,
+			&fk.DeleteRule,
+		); err != nil {
+			return nil, err
+		}
+		key := fmt.Sprintf("%s.%s", fk.TableName, fk.ConstraintName)
+		if _, ok := fkMap[key]; !ok {
+			fkMap[key] = fk
+			fks = append(fks, fk)
+		}
+		fkMap[key].ColumnNames = append(fkMap[key].ColumnNames, columnName)
+		fkMap[key].ReferencedColumnNames = append(fkMap[key].ReferencedColumnNames, referencedColumnName)
+	}
+	return fks, nil
+}
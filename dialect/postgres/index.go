@@ -0,0 +1,100 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/dev-cloverlab/carpenter/dialect/mysql"
+)
+
+func indexColumnNames(index mysql.Index) []string {
+	names := make([]string, 0, len(index))
+	for _, info := range index {
+		names = append(names, Quote(info.ColumnName))
+	}
+	return names
+}
+
+// primaryKeyName returns the default name of the primary key constraint.
+func primaryKeyName(table *mysql.Table) string {
+	return fmt.Sprintf("%s_pkey", table.TableName)
+}
+
+func indexToCreateSQL(table *mysql.Table, index mysql.Index) string {
+	unique := ""
+	if index.IsUniqueKey() {
+		unique = "unique "
+	}
+	using := ""
+	if t := strings.ToLower(index[0].IndexType); t != "" && t != "btree" {
+		using = fmt.Sprintf(" using %s", t)
+	}
+	return fmt.Sprintf("create %sindex if not exists %s on %s%s (%s)", unique, Quote(index.GetKeyName()), Quote(table.TableName), using, strings.Join(indexColumnNames(index), ","))
+}
+
+func indexToAddSQL(table *mysql.Table, index mysql.Index) string {
+	if index.IsPrimaryKey() {
+		return fmt.Sprintf(alterSQLFmt, Quote(table.TableName), fmt.Sprintf("add primary key (%s)", strings.Join(indexColumnNames(index), ",")))
+	}
+	return indexToCreateSQL(table, index)
+}
+
+// indexToDropSQL drops both of the constraint and the index because a unique key can be created in either way.
+func indexToDropSQL(table *mysql.Table, index mysql.Index) []string {
+	if index.IsPrimaryKey() {
+		return []string{fmt.Sprintf(alterSQLFmt, Quote(table.TableName), fmt.Sprintf("drop constraint if exists %s", Quote(primaryKeyName(table))))}
+	}
+	sqls := []string{}
+	if index.IsUniqueKey() {
+		sqls = append(sqls, fmt.Sprintf(alterSQLFmt, Quote(table.TableName), fmt.Sprintf("drop constraint if exists %s", Quote(index.GetKeyName()))))
+	}
+	return append(sqls, fmt.Sprintf("drop index if exists %s", Quote(index.GetKeyName())))
+}
+
+func GetIndices(db *sql.DB, table string) (mysql.Indices, error) {
+	selectCols := []string{
+		"t.relname",
+		"case when ix.indisunique then 0 else 1 end",
+		"case when ix.indisprimary then 'PRIMARY' else i.relname end",
+		"k.n",
+		"a.attname",
+		"upper(am.amname)",
+	}
+	query := fmt.Sprintf(`select %s from pg_catalog.pg_index ix
+	inner join pg_catalog.pg_class t on t.oid=ix.indrelid
+	inner join pg_catalog.pg_class i on i.oid=ix.indexrelid
+	inner join pg_catalog.pg_namespace n on n.oid=t.relnamespace
+	inner join pg_catalog.pg_am am on am.oid=i.relam
+	cross join lateral unnest(ix.indkey) with ordinality as k(attnum, n)
+	inner join pg_catalog.pg_attribute a on a.attrelid=t.oid and a.attnum=k.attnum
+	where n.nspname=current_schema() and t.relname=%s
+	order by i.relname, k.n`, strings.Join(selectCols, ","), QuoteString(table))
+	rows, err := db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("err: db.Query `%s' failed for reason %s", query, err)
+	}
+	defer rows.Close()
+
+	indices := mysql.Indices{}
+	idxMap := map[string]int{}
+	for rows.Next() {
+		idxCol := mysql.IndexColumn{Collation: "A"}
+		if err := rows.Scan(
+			&idxCol.Table,
+			&idxCol.NonUniue,
+			&idxCol.KeyName,
+			&idxCol.SeqInIndex,
+			&idxCol.ColumnName,
+			&idxCol.IndexType,
+		); err != nil {
+			return nil, err
+		}
+		if _, ok := idxMap[idxCol.KeyName]; !ok {
+			idxMap[idxCol.KeyName] = len(indices)
+			indices = append(indices, mysql.Index{})
+		}
+		indices[idxMap[idxCol.KeyName]] = append(indices[idxMap[idxCol.KeyName]], idxCol)
+	}
+	return indices, nil
+}
@@ -0,0 +1,205 @@
+package postgres
+
+import (
+	"database/sql"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/dev-cloverlab/carpenter/dialect/mysql"
+	_ "github.com/lib/pq"
+)
+
+var (
+	db     *sql.DB
+	schema = "carpenter_test"
+)
+
+func init() {
+	var err error
+	db, err = sql.Open("postgres", Dialect{}.DataSourceName("postgres://postgres@localhost:5432?sslmode=disable", schema))
+	if err != nil {
+		panic(err)
+	}
+}
+
+func TestMain(m *testing.M) {
+	_, err := db.Exec(`drop table if exists "design_test_child", "design_test"`)
+	if err != nil {
+		panic(err)
+	}
+	code := m.Run()
+	_, err = db.Exec(`drop table if exists "design_test_child", "design_test"`)
+	if err != nil {
+		panic(err)
+	}
+	os.Exit(code)
+}
+
+func TestCreate(t *testing.T) {
+	queries := []string{
+		`create table "design_test" (
+			"id" serial primary key,
+			"name" varchar(64) not null default '',
+			"email" varchar(255) not null,
+			"comment" text,
+			"created_at" timestamp not null default now(),
+			constraint "k1" unique ("email")
+		)`,
+		`create index "k2" on "design_test" ("name", "created_at")`,
+		`comment on column "design_test"."name" is 'user''s name'`,
+		`create table "design_test_child" (
+			"id" bigint generated by default as identity primary key,
+			"parent_id" integer not null,
+			constraint "fk_parent" foreign key ("parent_id") references "design_test" ("id") on delete cascade
+		)`,
+	}
+	for _, q := range queries {
+		if _, err := db.Exec(q); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tables, err := GetTables(db, schema, "design_test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{
+		"create table if not exists \"design_test\" (\n" +
+			"	\"id\" serial not null,\n" +
+			"	\"name\" character varying(64) not null default ''::character varying,\n" +
+			"	\"email\" character varying(255) not null,\n" +
+			"	\"comment\" text,\n" +
+			"	\"created_at\" timestamp without time zone not null default now(),\n" +
+			"	primary key (\"id\"),\n" +
+			"	constraint \"k1\" unique (\"email\")\n" +
+			")",
+		"create index if not exists \"k2\" on \"design_test\" (\"name\",\"created_at\")",
+		"comment on column \"design_test\".\"name\" is 'user''s name'",
+	}
+	actual := ToCreateSQL(tables[0])
+	if !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("err: create: unexpected SQL returned.\nactual:\n%s\nexpected:\n%s\n", actual, expected)
+	}
+
+	tables, err = GetTables(db, schema, "design_test_child")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected = []string{
+		"create table if not exists \"design_test_child\" (\n" +
+			"	\"id\" bigint not null generated by default as identity,\n" +
+			"	\"parent_id\" integer not null,\n" +
+			"	primary key (\"id\"),\n" +
+			"	constraint \"fk_parent\" foreign key (\"parent_id\") references \"design_test\" (\"id\") on delete cascade on update no action\n" +
+			")",
+	}
+	actual = ToCreateSQL(tables[0])
+	if !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("err: create: unexpected SQL returned.\nactual:\n%s\nexpected:\n%s\n", actual, expected)
+	}
+}
+
+func TestAlter(t *testing.T) {
+	tables, err := GetTables(db, schema, "design_test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	table := tables[0]
+	column := *table.Columns[3]
+	column.Nullable = "NO"
+	column.ColumnDefault = mysql.JsonNullString{NullString: sql.NullString{String: "''::text", Valid: true}}
+	column.ColumnComment = "free text"
+	expected := []string{
+		"alter table \"design_test\" drop column \"email\"",
+		"alter table \"design_test\" alter column \"comment\" type text,\n" +
+			"	alter column \"comment\" set not null,\n" +
+			"	alter column \"comment\" set default ''::text",
+		"create unique index if not exists \"k3\" on \"design_test\" (\"comment\")",
+		"comment on column \"design_test\".\"comment\" is 'free text'",
+	}
+	actual, err := ToAlterSQL(table, &mysql.Alter{
+		DropColumns:    mysql.Columns{table.Columns[2]},
+		ModifyColumns:  mysql.Columns{&column},
+		ModifyComments: mysql.Columns{&column},
+		AddIndices: []*mysql.AddIndex{
+			{Indices: mysql.Indices{{{Table: "design_test", KeyName: "k3", ColumnName: "comment", IndexType: "BTREE"}}}},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("err: alter: unexpected SQL returned.\nactual:\n%s\nexpected:\n%s\n", actual, expected)
+	}
+	for _, q := range actual {
+		if _, err := db.Exec(q); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestAlterSerial(t *testing.T) {
+	tables, err := GetTables(db, schema, "design_test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	table := tables[0]
+	column := *table.Columns[0]
+	column.ColumnType = "bigserial"
+	expected := []string{
+		"alter table \"design_test\" alter column \"id\" type bigint,\n" +
+			"	alter column \"id\" set not null",
+	}
+	actual, err := ToAlterSQL(table, &mysql.Alter{ModifyColumns: mysql.Columns{&column}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("err: alter: unexpected SQL returned.\nactual:\n%s\nexpected:\n%s\n", actual, expected)
+	}
+	for _, q := range actual {
+		if _, err := db.Exec(q); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tables, err = GetTables(db, schema, "design_test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actual := tables[0].Columns[0].ColumnType; actual != "bigserial" {
+		t.Errorf("err: the sequence default is lost: %s", actual)
+	}
+}
+
+func TestAlterUnsupported(t *testing.T) {
+	table := &mysql.Table{TableName: "design_test"}
+	_, err := ToAlterSQL(table, &mysql.Alter{
+		ConvertCharset: true,
+		AddPartitions:  mysql.Partitions{{PartitionName: "p1"}},
+	})
+	expected := "err: Can not alter character sets, partitions of table `design_test' on postgres driver"
+	if err == nil || err.Error() != expected {
+		t.Fatalf("err: unexpected error returned.\nactual:\n%v\nexpected:\n%s\n", err, expected)
+	}
+}
+
+func TestSeed(t *testing.T) {
+	cnk := &mysql.Chunk{
+		TableName:   "design_test",
+		ColumnNames: []string{"id", "name", "comment"},
+		Seeds: mysql.Seeds{
+			{ColumnData: []interface{}{float64(1), "it's", nil}},
+		},
+	}
+	expected := []string{
+		"insert into \"design_test\"(\"id\",\"name\",\"comment\")\n" +
+			"values\n" +
+			"(1,'it''s',null)\n" +
+			"on conflict (\"id\") do update set \"name\"=excluded.\"name\",\"comment\"=excluded.\"comment\"",
+	}
+	actual := ToReplaceSQL(cnk, []string{"id"})
+	if !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("err: replace: unexpected SQL returned.\nactual:\n%s\nexpected:\n%s\n", actual, expected)
+	}
+}
@@ -0,0 +1,11 @@
+package postgres
+
+// ForeignKeyCheck returns the statement which switches the session replication role.
+// Foreign keys are not checked in replica role because they are implemented as triggers.
+func ForeignKeyCheck(turnOn bool) string {
+	v := "replica"
+	if turnOn {
+		v = "origin"
+	}
+	return "set session_replication_role = " + v
+}
@@ -0,0 +1,224 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/dev-cloverlab/carpenter/dialect/mysql"
+)
+
+var (
+	createSQLFmt string = `create table if not exists %s (
+	%s
+)`
+	dropSQLFmt    string = `drop table if exists %s`
+	renameSQLFmt  string = `alter table %s rename to %s`
+	alterSQLFmt   string = `alter table %s %s`
+	commentSQLFmt string = `comment on %s %s is %s`
+)
+
+func ToCreateSQL(table *mysql.Table) []string {
+	sqls := make([]string, 0, len(table.Columns)+len(table.ForeignKeys)+1)
+	for _, column := range table.Columns {
+		sqls = append(sqls, columnToSQL(column))
+	}
+	indexSQLs := []string{}
+	for _, index := range table.Indices {
+		switch {
+		case index.IsPrimaryKey():
+			sqls = append(sqls, fmt.Sprintf("primary key (%s)", strings.Join(indexColumnNames(index), ",")))
+		case index.IsUniqueKey():
+			sqls = append(sqls, fmt.Sprintf("constraint %s unique (%s)", Quote(index.GetKeyName()), strings.Join(indexColumnNames(index), ",")))
+		default:
+			indexSQLs = append(indexSQLs, indexToCreateSQL(table, index))
+		}
+	}
+	for _, fk := range table.ForeignKeys {
+		sqls = append(sqls, foreignKeyToSQL(fk))
+	}
+	queries := []string{fmt.Sprintf(createSQLFmt, Quote(table.TableName), strings.Join(sqls, ",\n	"))}
+	queries = append(queries, indexSQLs...)
+	if table.TableComment != "" {
+		queries = append(queries, fmt.Sprintf(commentSQLFmt, "table", Quote(table.TableName), QuoteString(table.TableComment)))
+	}
+	for _, column := range table.Columns {
+		if column.HasComment() {
+			queries = append(queries, columnToCommentSQL(table, column))
+		}
+	}
+	return queries
+}
+
+func ToDropSQL(table *mysql.Table) string {
+	return fmt.Sprintf(dropSQLFmt, Quote(table.TableName))
+}
+
+func ToRenameSQL(table *mysql.Table, name string) string {
+	return fmt.Sprintf(renameSQLFmt, Quote(table.TableName), Quote(name))
+}
+
+// ToAlterSQL renders the differences to the statements.
+// It returns an error for the differences which can not be rendered, such as character sets,
+// because PostgreSQL has it for each database, and partitions, which are not supported yet.
+func ToAlterSQL(table *mysql.Table, alter *mysql.Alter) ([]string, error) {
+	if err := validateAlter(table, alter); err != nil {
+		return nil, err
+	}
+	queries := []string{}
+	actions := []string{}
+	for _, fk := range alter.DropForeignKeys {
+		actions = append(actions, fmt.Sprintf("drop constraint %s", Quote(fk.ConstraintName)))
+	}
+	queries = append(queries, toAlterSQL(table, actions)...)
+	for _, index := range alter.DropIndices {
+		queries = append(queries, indexToDropSQL(table, index)...)
+	}
+	actions = []string{}
+	for _, column := range alter.DropColumns {
+		actions = append(actions, fmt.Sprintf("drop column %s", Quote(column.ColumnName)))
+	}
+	queries = append(queries, toAlterSQL(table, actions)...)
+	modified := mysql.Columns{}
+	for _, change := range alter.ChangeColumns {
+		queries = append(queries, fmt.Sprintf(alterSQLFmt, Quote(table.TableName), fmt.Sprintf("rename column %s to %s", Quote(change.OldName), Quote(change.Column.ColumnName))))
+		modified = append(modified, change.Column)
+	}
+	for _, rename := range alter.RenameIndices {
+		queries = append(queries, fmt.Sprintf("alter index %s rename to %s", Quote(rename.Index.GetKeyName()), Quote(rename.Name)))
+	}
+	actions = []string{}
+	for _, column := range alter.AddColumns {
+		actions = append(actions, fmt.Sprintf("add column %s", columnToSQL(column)))
+	}
+	modified = append(modified, alter.ModifyColumns...)
+	for _, column := range modified {
+		actions = append(actions, columnToModifySQL(column)...)
+	}
+	queries = append(queries, toAlterSQL(table, actions)...)
+	for _, add := range alter.AddIndices {
+		for _, index := range add.Replace {
+			queries = append(queries, indexToDropSQL(table, index)...)
+		}
+		for _, index := range add.Indices {
+			queries = append(queries, indexToAddSQL(table, index))
+		}
+	}
+	actions = []string{}
+	for _, fk := range alter.AddForeignKeys {
+		actions = append(actions, fmt.Sprintf("add %s", foreignKeyToSQL(fk)))
+	}
+	queries = append(queries, toAlterSQL(table, actions)...)
+	for _, column := range alter.AddColumns {
+		if column.HasComment() {
+			queries = append(queries, columnToCommentSQL(table, column))
+		}
+	}
+	for _, column := range alter.ModifyComments {
+		queries = append(queries, columnToCommentSQL(table, column))
+	}
+	return queries, nil
+}
+
+func validateAlter(table *mysql.Table, alter *mysql.Alter) error {
+	unsupported := []string{}
+	if alter.ConvertCharset || len(alter.ModifyCharsetColumns) > 0 {
+		unsupported = append(unsupported, "character sets")
+	}
+	if len(alter.AlterIndices) > 0 {
+		unsupported = append(unsupported, "index visibility")
+	}
+	if len(alter.DropCheckConstraints) > 0 || len(alter.AddCheckConstraints) > 0 || len(alter.AlterCheckConstraints) > 0 {
+		unsupported = append(unsupported, "check constraints")
+	}
+	if len(alter.Partitions) > 0 || alter.RemovePartitioning ||
+		len(alter.DropPartitions) > 0 || len(alter.ReorganizePartitions) > 0 || len(alter.AddPartitions) > 0 {
+		unsupported = append(unsupported, "partitions")
+	}
+	if len(unsupported) > 0 {
+		return fmt.Errorf("err: Can not alter %s of table `%s' on postgres driver", strings.Join(unsupported, ", "), table.TableName)
+	}
+	return nil
+}
+
+func toAlterSQL(table *mysql.Table, actions []string) []string {
+	if len(actions) <= 0 {
+		return []string{}
+	}
+	return []string{fmt.Sprintf(alterSQLFmt, Quote(table.TableName), strings.Join(actions, ",\n	"))}
+}
+
+func GetTables(db *sql.DB, schema string, tableNames ...string) (mysql.Tables, error) {
+	selectCols := []string{
+		"t.table_catalog",
+		"t.table_schema",
+		"t.table_name",
+		"t.table_type",
+		"coalesce(obj_description(c.oid, 'pg_class'), '')",
+	}
+	query := fmt.Sprintf(`select %s from information_schema.tables t
+	inner join pg_catalog.pg_namespace n on n.nspname=t.table_schema
+	inner join pg_catalog.pg_class c on c.relnamespace=n.oid and c.relname=t.table_name
+	where t.table_catalog=%s and t.table_schema=current_schema() and t.table_type='BASE TABLE'`, strings.Join(selectCols, ","), QuoteString(schema))
+	if len(tableNames) > 0 {
+		tn := make([]string, 0, len(tableNames))
+		for _, t := range tableNames {
+			tn = append(tn, QuoteString(t))
+		}
+		query = fmt.Sprintf("%s and t.table_name in (%s)", query, strings.Join(tn, ","))
+	}
+	rows, err := db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("err: db.Query `%s' failed for reason %s", query, err)
+	}
+	defer rows.Close()
+
+	tables := mysql.Tables{}
+	for rows.Next() {
+		table := &mysql.Table{}
+		if err := rows.Scan(
+			&table.TableCatalog,
+			&table.TableSchema,
+			&table.TableName,
+			&table.TableType,
+			&table.TableComment,
+		); err != nil {
+			return nil, err
+		}
+		tables = append(tables, table)
+	}
+	columns, err := GetColumns(db)
+	if err != nil {
+		return nil, err
+	}
+	foreignKeys, err := GetForeignKeys(db)
+	if err != nil {
+		return nil, err
+	}
+	for i, table := range tables {
+		indices, err := GetIndices(db, table.TableName)
+		if err != nil {
+			return nil, err
+		}
+		c := mysql.Columns{}
+		for _, v := range columns {
+			if table.TableName != v.TableName {
+				continue
+			}
+			c = append(c, v)
+		}
+		fks := mysql.ForeignKeys{}
+		for _, v := range foreignKeys {
+			if table.TableName != v.TableName {
+				continue
+			}
+			fks = append(fks, v)
+		}
+		if len(fks) > 0 {
+			tables[i].ForeignKeys = fks
+		}
+		tables[i].Columns = c
+		tables[i].Indices = indices
+	}
+	return tables, nil
+}
@@ -0,0 +1,86 @@
+package sqlite
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/dev-cloverlab/carpenter/dialect/mysql"
+)
+
+func columnToSQL(column *mysql.Column) string {
+	token := []string{Quote(column.ColumnName)}
+	if column.ColumnType != "" {
+		token = append(token, column.ColumnType)
+	}
+	if !column.IsNullable() {
+		token = append(token, "not null")
+	}
+	if column.HasDefault() {
+		token = append(token, "default", formatDefault(column))
+	}
+	if isAutoIncrement(column) {
+		token = append(token, "primary key autoincrement")
+	}
+	return strings.Join(token, " ")
+}
+
+func isAutoIncrement(column *mysql.Column) bool {
+	return column.HasExtra() && column.Extra.String == "autoincrement"
+}
+
+// formatDefault returns the default value as it is, because SQLite reports it as an expression.
+func formatDefault(column *mysql.Column) string {
+	if column.ColumnDefault.String == "" {
+		return QuoteString("")
+	}
+	return column.ColumnDefault.String
+}
+
+// dataType returns the type name without the length like 'varchar' of 'varchar(255)'.
+func dataType(columnType string) string {
+	if pos := strings.Index(columnType, "("); pos >= 0 {
+		columnType = columnType[:pos]
+	}
+	return strings.TrimSpace(columnType)
+}
+
+func GetColumns(db *sql.DB, schema, table string) (mysql.Columns, error) {
+	query := fmt.Sprintf("pragma table_info(%s)", Quote(table))
+	rows, err := db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("err: db.Query `%s' failed for reason %s", query, err)
+	}
+	defer rows.Close()
+
+	columns := mysql.Columns{}
+	for rows.Next() {
+		var cid, notNull, pk int32
+		column := &mysql.Column{
+			TableSchema: schema,
+			TableName:   table,
+			Nullable:    "YES",
+		}
+		if err := rows.Scan(
+			&cid,
+			&column.ColumnName,
+			&column.ColumnType,
+			&notNull,
+			&column.ColumnDefault,
+			&pk,
+		); err != nil {
+			return nil, err
+		}
+		column.OrdinalPosition = cid + 1
+		column.ColumnType = strings.ToLower(column.ColumnType)
+		column.DataType = dataType(column.ColumnType)
+		if notNull != 0 {
+			column.Nullable = "NO"
+		}
+		if pk > 0 {
+			column.ColumnKey = "PRI"
+		}
+		columns = append(columns, column)
+	}
+	return columns, rows.Err()
+}
@@ -0,0 +1,81 @@
+package sqlite
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/dev-cloverlab/carpenter/dialect/mysql"
+)
+
+// Dialect implements dialect.Dialect for SQLite.
+// The data source is the path of the database file and the schema option is only used as the name in the JSON files.
+type Dialect struct{}
+
+func (Dialect) DriverName() string {
+	return "sqlite3"
+}
+
+// DataSourceName returns the path of the database file with the option which enables the foreign key constraints.
+func (Dialect) DataSourceName(dataSource, schema string) string {
+	if strings.Contains(dataSource, "?") {
+		return fmt.Sprintf("%s&_foreign_keys=1", dataSource)
+	}
+	return fmt.Sprintf("%s?_foreign_keys=1", dataSource)
+}
+
+func (Dialect) Quote(name string) string {
+	return Quote(name)
+}
+
+func (Dialect) QuoteString(value string) string {
+	return QuoteString(value)
+}
+
+func (Dialect) ForeignKeyCheck(turnOn bool) string {
+	return ForeignKeyCheck(turnOn)
+}
+
+func (Dialect) GetTables(db *sql.DB, schema string, tableNames ...string) (mysql.Tables, error) {
+	return GetTables(db, schema, tableNames...)
+}
+
+func (Dialect) GetIndices(db *sql.DB, table string) (mysql.Indices, error) {
+	return GetIndices(db, table)
+}
+
+func (Dialect) Placeholder(n int) string {
+	return "?"
+}
+
+func (Dialect) ToCreateSQL(table *mysql.Table) []string {
+	return ToCreateSQL(table)
+}
+
+func (Dialect) ToDropSQL(table *mysql.Table) string {
+	return ToDropSQL(table)
+}
+
+func (Dialect) ToRenameSQL(table *mysql.Table, name string) string {
+	return ToRenameSQL(table, name)
+}
+
+func (Dialect) ToAlterSQL(table *mysql.Table, alter *mysql.Alter) ([]string, error) {
+	return ToAlterSQL(table, alter)
+}
+
+func (Dialect) ToDeleteAllSQL(cnk *mysql.Chunk) string {
+	return ToTruncateSQL(cnk)
+}
+
+func (Dialect) ToInsertSQL(cnk *mysql.Chunk) []string {
+	return ToInsertSQL(cnk)
+}
+
+func (Dialect) ToReplaceSQL(cnk *mysql.Chunk, keyNames []string) []string {
+	return ToReplaceSQL(cnk, keyNames)
+}
+
+func (Dialect) ToDeleteSQL(cnk *mysql.Chunk, colIdxs []int) []string {
+	return ToDeleteSQL(cnk, colIdxs)
+}
@@ -0,0 +1,88 @@
+package sqlite
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/dev-cloverlab/carpenter/dialect/mysql"
+)
+
+// foreignKeyNameFmt makes the name of a foreign key from the table and the column names,
+// because SQLite does not report the constraint names.
+var foreignKeyNameFmt string = "fk_%s_%s"
+
+func foreignKeyToSQL(fk *mysql.ForeignKey) string {
+	token := []string{
+		"constraint", Quote(fk.ConstraintName),
+		fmt.Sprintf("foreign key (%s)", strings.Join(QuoteMulti(fk.ColumnNames), ",")),
+		fmt.Sprintf("references %s (%s)", Quote(fk.ReferencedTableName), strings.Join(QuoteMulti(fk.ReferencedColumnNames), ",")),
+	}
+	if fk.DeleteRule != "" {
+		token = append(token, "on delete", strings.ToLower(fk.DeleteRule))
+	}
+	if fk.UpdateRule != "" {
+		token = append(token, "on update", strings.ToLower(fk.UpdateRule))
+	}
+	return strings.Join(token, " ")
+}
+
+func GetForeignKeys(db *sql.DB, schema, table string) (mysql.ForeignKeys, error) {
+	query := fmt.Sprintf(`select id, "table", "from", "to", on_update, on_delete from pragma_foreign_key_list(%s) order by id, seq`, QuoteString(table))
+	rows, err := db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("err: db.Query `%s' failed for reason %s", query, err)
+	}
+	defer rows.Close()
+
+	fks := mysql.ForeignKeys{}
+	fkMap := map[int]*mysql.ForeignKey{}
+	for rows.Next() {
+		var id int
+		var columnName string
+		var referencedColumnName sql.NullString
+		fk := &mysql.ForeignKey{
+			TableSchema:           schema,
+			TableName:             table,
+			ReferencedTableSchema: schema,
+		}
+		if err := rows.Scan(
+			&id,
+			&fk.ReferencedTableName,
+			&columnName,
+			&referencedColumnName,
+			&fk.UpdateRule,
+			&fk.DeleteRule,
+		); err != nil {
+			return nil, err
+		}
+		if _, ok := fkMap[id]; !ok {
+			fkMap[id] = fk
+			fks = append(fks, fk)
+		}
+		fkMap[id].ColumnNames = append(fkMap[id].ColumnNames, columnName)
+		fkMap[id].ReferencedColumnNames = append(fkMap[id].ReferencedColumnNames, referencedColumnName.String)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	for _, fk := range fks {
+		fk.ConstraintName = fmt.Sprintf(foreignKeyNameFmt, table, strings.Join(fk.ColumnNames, "_"))
+		// the referenced columns are omitted when the key refers to the primary key
+		if fk.ReferencedColumnNames[0] == "" {
+			primary, err := getPrimaryKey(db, fk.ReferencedTableName)
+			if err != nil {
+				return nil, err
+			}
+			fk.ReferencedColumnNames = []string{}
+			for _, info := range primary {
+				fk.ReferencedColumnNames = append(fk.ReferencedColumnNames, info.ColumnName)
+			}
+		}
+	}
+	sort.Slice(fks, func(i, j int) bool {
+		return fks[i].ConstraintName < fks[j].ConstraintName
+	})
+	return fks, nil
+}
@@ -0,0 +1,159 @@
+package sqlite
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/dev-cloverlab/carpenter/dialect/mysql"
+)
+
+// autoIndexPrefix is the prefix of the indices which SQLite creates for the unique constraints.
+const autoIndexPrefix = "sqlite_autoindex_"
+
+func indexColumnNames(index mysql.Index) []string {
+	names := make([]string, 0, len(index))
+	for _, info := range index {
+		names = append(names, Quote(info.ColumnName))
+	}
+	return names
+}
+
+// isAutoIndex reports whether the index is created by the unique constraint of the table definition.
+// It can not be dropped or created without rebuilding the table.
+func isAutoIndex(index mysql.Index) bool {
+	return strings.HasPrefix(index.GetKeyName(), autoIndexPrefix)
+}
+
+func indicesToCreateSQL(table *mysql.Table) []string {
+	sqls := []string{}
+	for _, index := range table.Indices {
+		if index.IsPrimaryKey() || isAutoIndex(index) {
+			continue
+		}
+		sqls = append(sqls, indexToCreateSQL(table, index))
+	}
+	return sqls
+}
+
+func indexToCreateSQL(table *mysql.Table, index mysql.Index) string {
+	unique := ""
+	if index.IsUniqueKey() {
+		unique = "unique "
+	}
+	return fmt.Sprintf("create %sindex if not exists %s on %s (%s)", unique, Quote(index.GetKeyName()), Quote(table.TableName), strings.Join(indexColumnNames(index), ","))
+}
+
+func indexToDropSQL(index mysql.Index) string {
+	return fmt.Sprintf("drop index if exists %s", Quote(index.GetKeyName()))
+}
+
+// GetIndices returns the primary key and the indices of the table.
+// The primary key is read from the columns because an integer primary key is an alias of the rowid and is not listed as an index.
+func GetIndices(db *sql.DB, table string) (mysql.Indices, error) {
+	indices := mysql.Indices{}
+	primary, err := getPrimaryKey(db, table)
+	if err != nil {
+		return nil, err
+	}
+	if len(primary) > 0 {
+		indices = append(indices, primary)
+	}
+
+	query := fmt.Sprintf("pragma index_list(%s)", Quote(table))
+	rows, err := db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("err: db.Query `%s' failed for reason %s", query, err)
+	}
+	defer rows.Close()
+
+	keyNames := []string{}
+	nonUniques := map[string]int8{}
+	for rows.Next() {
+		var seq, unique, partial int
+		var name, origin string
+		if err := rows.Scan(&seq, &name, &unique, &origin, &partial); err != nil {
+			return nil, err
+		}
+		if origin == "pk" {
+			continue
+		}
+		keyNames = append(keyNames, name)
+		nonUniques[name] = 1
+		if unique != 0 {
+			nonUniques[name] = 0
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	sort.Strings(keyNames)
+	for _, keyName := range keyNames {
+		index, err := getIndexColumns(db, table, keyName, nonUniques[keyName])
+		if err != nil {
+			return nil, err
+		}
+		if len(index) > 0 {
+			indices = append(indices, index)
+		}
+	}
+	return indices, nil
+}
+
+func getPrimaryKey(db *sql.DB, table string) (mysql.Index, error) {
+	query := fmt.Sprintf("select name from pragma_table_info(%s) where pk > 0 order by pk", QuoteString(table))
+	rows, err := db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("err: db.Query `%s' failed for reason %s", query, err)
+	}
+	defer rows.Close()
+
+	index := mysql.Index{}
+	for rows.Next() {
+		idxCol := mysql.IndexColumn{
+			Table:      table,
+			KeyName:    "PRIMARY",
+			SeqInIndex: int32(len(index) + 1),
+			Collation:  "A",
+			IndexType:  "BTREE",
+		}
+		if err := rows.Scan(&idxCol.ColumnName); err != nil {
+			return nil, err
+		}
+		index = append(index, idxCol)
+	}
+	return index, rows.Err()
+}
+
+// getIndexColumns returns the columns of the index. Expressions are not supported and skipped.
+func getIndexColumns(db *sql.DB, table, keyName string, nonUnique int8) (mysql.Index, error) {
+	query := fmt.Sprintf("pragma index_info(%s)", Quote(keyName))
+	rows, err := db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("err: db.Query `%s' failed for reason %s", query, err)
+	}
+	defer rows.Close()
+
+	index := mysql.Index{}
+	for rows.Next() {
+		var seqno, cid int32
+		var name sql.NullString
+		if err := rows.Scan(&seqno, &cid, &name); err != nil {
+			return nil, err
+		}
+		if !name.Valid {
+			continue
+		}
+		index = append(index, mysql.IndexColumn{
+			Table:      table,
+			NonUniue:   nonUnique,
+			KeyName:    keyName,
+			SeqInIndex: seqno + 1,
+			ColumnName: name.String,
+			Collation:  "A",
+			IndexType:  "BTREE",
+		})
+	}
+	return index, rows.Err()
+}
@@ -0,0 +1,138 @@
+package sqlite
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dev-cloverlab/carpenter/dialect/mysql"
+)
+
+var (
+	defaultBulkSize int    = 2000
+	deleteAllSQLFmt string = `delete from %s`
+	insertSQLFmt    string = `insert into %s(%s)
+values
+%s`
+	deleteSQLFmt string = `delete from %s where %s in (
+%s
+)`
+	upsertSQLFmt string = `insert into %s(%s)
+values
+%s
+on conflict (%s) do %s`
+)
+
+// ToTruncateSQL uses delete statement because SQLite does not have truncate statement.
+func ToTruncateSQL(cnk *mysql.Chunk) string {
+	return fmt.Sprintf(deleteAllSQLFmt, Quote(cnk.TableName))
+}
+
+func ToInsertSQL(cnk *mysql.Chunk) []string {
+	columnStr := strings.Join(QuoteMulti(cnk.ColumnNames), ",")
+	queries := []string{}
+	for _, seeds := range splitSeeds(cnk.Seeds) {
+		queries = append(queries, fmt.Sprintf(insertSQLFmt, Quote(cnk.TableName), columnStr, strings.Join(toValueSQLs(seeds), ",\n")))
+	}
+	return queries
+}
+
+// ToReplaceSQL generates insert statements which update the rows conflicted with the specified key columns.
+func ToReplaceSQL(cnk *mysql.Chunk, keyNames []string) []string {
+	columnStr := strings.Join(QuoteMulti(cnk.ColumnNames), ",")
+	keys := map[string]struct{}{}
+	for _, name := range keyNames {
+		keys[name] = struct{}{}
+	}
+	sets := []string{}
+	for _, name := range cnk.ColumnNames {
+		if _, ok := keys[name]; ok {
+			continue
+		}
+		sets = append(sets, fmt.Sprintf("%s=excluded.%s", Quote(name), Quote(name)))
+	}
+	action := "nothing"
+	if len(sets) > 0 {
+		action = fmt.Sprintf("update set %s", strings.Join(sets, ","))
+	}
+	queries := []string{}
+	for _, seeds := range splitSeeds(cnk.Seeds) {
+		queries = append(queries, fmt.Sprintf(upsertSQLFmt, Quote(cnk.TableName), columnStr, strings.Join(toValueSQLs(seeds), ",\n"), strings.Join(QuoteMulti(keyNames), ","), action))
+	}
+	return queries
+}
+
+// ToDeleteSQL deletes the rows which have the values of the specified columns.
+// A composite key is compared as a row constructor.
+func ToDeleteSQL(cnk *mysql.Chunk, colIdxs []int) []string {
+	names := make([]string, 0, len(colIdxs))
+	for _, colIdx := range colIdxs {
+		names = append(names, cnk.ColumnNames[colIdx])
+	}
+	columnStr := strings.Join(QuoteMulti(names), ",")
+	if len(colIdxs) > 1 {
+		columnStr = fmt.Sprintf("(%s)", columnStr)
+	}
+	queries := []string{}
+	for _, seeds := range splitSeeds(cnk.Seeds) {
+		values := make([]string, 0, len(seeds))
+		for _, seed := range seeds {
+			str := make([]string, 0, len(colIdxs))
+			for _, colIdx := range colIdxs {
+				str = append(str, toString(seed.ColumnData[colIdx]))
+			}
+			if len(str) > 1 {
+				values = append(values, fmt.Sprintf("(%s)", strings.Join(str, ",")))
+			} else {
+				values = append(values, str[0])
+			}
+		}
+		queries = append(queries, fmt.Sprintf(deleteSQLFmt, Quote(cnk.TableName), columnStr, strings.Join(values, ",\n")))
+	}
+	return queries
+}
+
+func splitSeeds(seeds mysql.Seeds) []mysql.Seeds {
+	chunks := []mysql.Seeds{}
+	for len(seeds) > defaultBulkSize {
+		chunks = append(chunks, seeds[:defaultBulkSize])
+		seeds = seeds[defaultBulkSize:]
+	}
+	if len(seeds) > 0 {
+		chunks = append(chunks, seeds)
+	}
+	return chunks
+}
+
+func toValueSQLs(seeds mysql.Seeds) []string {
+	sqls := make([]string, 0, len(seeds))
+	for _, seed := range seeds {
+		str := make([]string, 0, len(seed.ColumnData))
+		for _, data := range seed.ColumnData {
+			str = append(str, toString(data))
+		}
+		sqls = append(sqls, fmt.Sprintf("(%s)", strings.Join(str, ",")))
+	}
+	return sqls
+}
+
+func toString(data interface{}) (str string) {
+	switch data.(type) {
+	case nil:
+		str = "null"
+	case string:
+		// a string literal can not contain NUL, so it is concatenated as char(0)
+		parts := strings.Split(data.(string), "\x00")
+		for i, part := range parts {
+			parts[i] = QuoteString(part)
+		}
+		str = strings.Join(parts, "||char(0)||")
+	case time.Time:
+		str = QuoteString(data.(time.Time).Format(mysql.TimeFmt))
+	case []byte:
+		str = fmt.Sprintf("X'%x'", data.([]byte))
+	default:
+		str = fmt.Sprintf("%v", data)
+	}
+	return str
+}
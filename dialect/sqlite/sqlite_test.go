@@ -0,0 +1,309 @@
+package sqlite
+
+import (
+	"database/sql"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/dev-cloverlab/carpenter/dialect/mysql"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+var (
+	db     *sql.DB
+	schema = "main"
+)
+
+func TestMain(m *testing.M) {
+	dir, err := ioutil.TempDir("", "carpenter")
+	if err != nil {
+		panic(err)
+	}
+	db, err = sql.Open("sqlite3", Dialect{}.DataSourceName(filepath.Join(dir, "test.db"), schema))
+	if err != nil {
+		panic(err)
+	}
+	db.SetMaxOpenConns(1)
+	code := m.Run()
+	db.Close()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+func exec(t *testing.T, queries ...string) {
+	for _, q := range queries {
+		if _, err := db.Exec(q); err != nil {
+			t.Fatalf("%s: %s", q, err)
+		}
+	}
+}
+
+func getTable(t *testing.T, name string) *mysql.Table {
+	tables, err := GetTables(db, schema, name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tables) != 1 {
+		t.Fatalf("table `%s' is not found", name)
+	}
+	return tables[0]
+}
+
+// getChunk returns the rows of the table as they are scanned.
+func getChunk(t *testing.T, name string) *mysql.Chunk {
+	rows, err := db.Query(fmt.Sprintf("select * from %s", Quote(name)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	columns, err := rows.Columns()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cnk := &mysql.Chunk{TableName: name, ColumnNames: columns, Seeds: mysql.Seeds{}}
+	for rows.Next() {
+		holders := make([]interface{}, len(columns))
+		holderPtrs := make([]interface{}, len(columns))
+		for i := range holders {
+			holderPtrs[i] = &holders[i]
+		}
+		if err := rows.Scan(holderPtrs...); err != nil {
+			t.Fatal(err)
+		}
+		cnk.Seeds = append(cnk.Seeds, mysql.Seed{ColumnData: holders})
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+	return cnk
+}
+
+func TestCreate(t *testing.T) {
+	exec(t,
+		`create table "design_test" (
+			"id" integer primary key autoincrement,
+			"name" varchar(64) not null default '',
+			"email" text not null unique,
+			"created_at" datetime not null default current_timestamp
+		)`,
+		`create index "k1" on "design_test" ("name", "created_at")`,
+		`create table "design_test_child" (
+			"parent_id" integer not null references "design_test" on delete cascade,
+			"seq" integer not null,
+			primary key ("parent_id", "seq")
+		)`,
+	)
+	defer exec(t, `drop table "design_test_child"`, `drop table "design_test"`)
+
+	table := getTable(t, "design_test")
+	expected := []string{
+		"create table if not exists \"design_test\" (\n" +
+			"	\"id\" integer primary key autoincrement,\n" +
+			"	\"name\" varchar(64) not null default '',\n" +
+			"	\"email\" text not null,\n" +
+			"	\"created_at\" datetime not null default current_timestamp,\n" +
+			"	unique (\"email\")\n" +
+			")",
+		"create index if not exists \"k1\" on \"design_test\" (\"name\",\"created_at\")",
+	}
+	if actual := ToCreateSQL(table); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("err: unexpected create statements.\nexpected %q\nbut actual %q", expected, actual)
+	}
+
+	child := getTable(t, "design_test_child")
+	expected = []string{
+		"create table if not exists \"design_test_child\" (\n" +
+			"	\"parent_id\" integer not null,\n" +
+			"	\"seq\" integer not null,\n" +
+			"	primary key (\"parent_id\",\"seq\"),\n" +
+			"	constraint \"fk_design_test_child_parent_id\" foreign key (\"parent_id\") references \"design_test\" (\"id\") on delete cascade on update no action\n" +
+			")",
+	}
+	if actual := ToCreateSQL(child); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("err: unexpected create statements.\nexpected %q\nbut actual %q", expected, actual)
+	}
+
+	// the generated statements reproduce the same definition
+	exec(t, `drop table "design_test_child"`, `drop table "design_test"`)
+	exec(t, ToCreateSQL(table)...)
+	exec(t, ToCreateSQL(child)...)
+	if actual := getTable(t, "design_test"); !reflect.DeepEqual(actual, table) {
+		t.Errorf("err: unexpected table.\nexpected %+v\nbut actual %+v", table, actual)
+	}
+	if actual := getTable(t, "design_test_child"); !reflect.DeepEqual(actual, child) {
+		t.Errorf("err: unexpected table.\nexpected %+v\nbut actual %+v", child, actual)
+	}
+}
+
+func TestAlter(t *testing.T) {
+	exec(t, `create table "alter_test" ("id" integer not null primary key, "name" text not null)`)
+	defer exec(t, `drop table "alter_test"`)
+
+	table := getTable(t, "alter_test")
+	alter := &mysql.Alter{
+		AddColumns: mysql.Columns{
+			&mysql.Column{ColumnName: "email", ColumnType: "text", Nullable: "YES"},
+		},
+		AddIndices: []*mysql.AddIndex{
+			{Indices: mysql.Indices{
+				mysql.Index{{Table: "alter_test", NonUniue: 0, KeyName: "uniq_email", SeqInIndex: 1, ColumnName: "email"}},
+			}},
+		},
+	}
+	table.Columns = append(table.Columns, alter.AddColumns...)
+	expected := []string{
+		`alter table "alter_test" add column "email" text`,
+		`create unique index if not exists "uniq_email" on "alter_test" ("email")`,
+	}
+	actual, err := ToAlterSQL(table, alter)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("err: unexpected alter statements.\nexpected %q\nbut actual %q", expected, actual)
+	}
+	exec(t, actual...)
+	if indices := getTable(t, "alter_test").Indices; len(indices) != 2 || indices[1].GetKeyName() != "uniq_email" {
+		t.Errorf("err: unexpected indices %+v", indices)
+	}
+}
+
+func TestAlterUnsupported(t *testing.T) {
+	table := &mysql.Table{TableName: "alter_test"}
+	_, err := ToAlterSQL(table, &mysql.Alter{
+		AddCheckConstraints: mysql.CheckConstraints{{ConstraintName: "c1", CheckClause: "id > 0"}},
+		ModifyComments:      mysql.Columns{{ColumnName: "id", ColumnComment: "identifier"}},
+	})
+	expected := "err: Can not alter check constraints, comments of table `alter_test' on sqlite driver"
+	if err == nil || err.Error() != expected {
+		t.Fatalf("err: unexpected error returned.\nactual:\n%v\nexpected:\n%s\n", err, expected)
+	}
+}
+
+func TestRebuild(t *testing.T) {
+	exec(t,
+		`create table "rebuild_test" ("id" integer not null primary key, "name" text not null, "memo" text)`,
+		`create index "k_name" on "rebuild_test" ("name")`,
+		`insert into "rebuild_test" values (1, 'foo', 'a'), (2, 'bar', 'b')`,
+	)
+	defer exec(t, `drop table "rebuild_test"`)
+
+	table := getTable(t, "rebuild_test")
+	nickname := &mysql.Column{TableName: "rebuild_test", ColumnName: "nickname", ColumnType: "varchar(32)", Nullable: "NO"}
+	count := &mysql.Column{TableName: "rebuild_test", ColumnName: "count", ColumnType: "integer", Nullable: "NO"}
+	count.ColumnDefault.Valid = true
+	count.ColumnDefault.String = "0"
+	alter := &mysql.Alter{
+		DropColumns:   mysql.Columns{table.Columns[2]},
+		ChangeColumns: []*mysql.ChangeColumn{{OldName: "name", Column: nickname}},
+		AddColumns:    mysql.Columns{count},
+	}
+	table.Columns = mysql.Columns{table.Columns[0], nickname, count}
+	table.Indices[1] = mysql.Index{{Table: "rebuild_test", NonUniue: 1, KeyName: "k_name", SeqInIndex: 1, ColumnName: "nickname"}}
+	expected := []string{
+		"pragma foreign_keys = off",
+		"create table if not exists \"_carpenter_rebuild_test\" (\n" +
+			"	\"id\" integer not null,\n" +
+			"	\"nickname\" varchar(32) not null,\n" +
+			"	\"count\" integer not null default 0,\n" +
+			"	primary key (\"id\")\n" +
+			")",
+		`insert into "_carpenter_rebuild_test"("id","nickname") select "id","name" from "rebuild_test"`,
+		`drop table if exists "rebuild_test"`,
+		`alter table "_carpenter_rebuild_test" rename to "rebuild_test"`,
+		`create index if not exists "k_name" on "rebuild_test" ("nickname")`,
+		"pragma foreign_keys = on",
+	}
+	actual, err := ToAlterSQL(table, alter)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("err: unexpected alter statements.\nexpected %q\nbut actual %q", expected, actual)
+	}
+	exec(t, actual...)
+
+	rows, err := db.Query(`select "id", "nickname", "count" from "rebuild_test" order by "id"`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	names := []string{}
+	for rows.Next() {
+		var id, count int
+		var name string
+		if err := rows.Scan(&id, &name, &count); err != nil {
+			t.Fatal(err)
+		}
+		names = append(names, name)
+	}
+	if !reflect.DeepEqual(names, []string{"foo", "bar"}) {
+		t.Errorf("err: rows are not copied %v", names)
+	}
+	if indices := getTable(t, "rebuild_test").Indices; !reflect.DeepEqual(indices[1].GetKeyName(), "k_name") || indices[1][0].ColumnName != "nickname" {
+		t.Errorf("err: unexpected indices %+v", indices)
+	}
+}
+
+func TestSeed(t *testing.T) {
+	exec(t,
+		`create table "seed_test" ("id" integer not null primary key, "name" text, "score" real)`,
+		`insert into "seed_test" values (1, 'foo', 1.5), (2, null, 2)`,
+	)
+	defer exec(t, `drop table "seed_test"`)
+
+	cnk := getChunk(t, "seed_test")
+	expected := mysql.Seeds{
+		{ColumnData: []interface{}{int64(1), "foo", float64(1.5)}},
+		{ColumnData: []interface{}{int64(2), nil, float64(2)}},
+	}
+	if !reflect.DeepEqual(cnk.Seeds, expected) {
+		t.Fatalf("err: unexpected seeds.\nexpected %v\nbut actual %v", expected, cnk.Seeds)
+	}
+
+	cnk.Seeds = mysql.Seeds{
+		{ColumnData: []interface{}{int64(2), "bar", float64(3)}},
+		{ColumnData: []interface{}{int64(3), "it's", nil}},
+	}
+	exec(t, ToReplaceSQL(cnk, []string{"id"})...)
+	exec(t, ToDeleteSQL(&mysql.Chunk{TableName: "seed_test", ColumnNames: cnk.ColumnNames, Seeds: expected[:1]}, []int{0})...)
+	actual := getChunk(t, "seed_test")
+	if !reflect.DeepEqual(actual.Seeds, cnk.Seeds) {
+		t.Errorf("err: unexpected seeds.\nexpected %v\nbut actual %v", cnk.Seeds, actual.Seeds)
+	}
+	exec(t, ToTruncateSQL(cnk))
+	if actual := getChunk(t, "seed_test"); len(actual.Seeds) != 0 {
+		t.Errorf("err: rows are not deleted %v", actual)
+	}
+}
+
+func FuzzSeedString(f *testing.F) {
+	for _, s := range []string{"", "it's", `C:\`, "a\x00b", "\x00", "\n\r", "日本語", "'); drop table x; --"} {
+		f.Add(s)
+	}
+	if _, err := db.Exec(`create table "fuzz_test" ("id" integer not null primary key, "value" text)`); err != nil {
+		f.Fatal(err)
+	}
+	defer db.Exec(`drop table "fuzz_test"`)
+	f.Fuzz(func(t *testing.T, s string) {
+		cnk := &mysql.Chunk{
+			TableName:   "fuzz_test",
+			ColumnNames: []string{"id", "value"},
+			Seeds:       mysql.Seeds{{ColumnData: []interface{}{float64(1), s}}},
+		}
+		exec(t, ToTruncateSQL(cnk))
+		exec(t, ToInsertSQL(cnk)...)
+		exec(t, ToReplaceSQL(cnk, []string{"id"})...)
+		var actual string
+		if err := db.QueryRow(`select "value" from "fuzz_test" where "id" = 1`).Scan(&actual); err != nil {
+			t.Fatal(err)
+		}
+		if actual != s {
+			t.Fatalf("err: %q is stored as %q", s, actual)
+		}
+	})
+}
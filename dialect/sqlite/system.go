@@ -0,0 +1,10 @@
+package sqlite
+
+// ForeignKeyCheck returns the pragma statement which switches the foreign key constraints.
+func ForeignKeyCheck(turnOn bool) string {
+	v := "off"
+	if turnOn {
+		v = "on"
+	}
+	return "pragma foreign_keys = " + v
+}
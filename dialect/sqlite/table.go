@@ -0,0 +1,255 @@
+package sqlite
+
+import (
+	"database/sql"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/dev-cloverlab/carpenter/dialect/mysql"
+)
+
+var (
+	createSQLFmt string = `create table if not exists %s (
+	%s
+)`
+	dropSQLFmt      string = `drop table if exists %s`
+	renameSQLFmt    string = `alter table %s rename to %s`
+	alterSQLFmt     string = `alter table %s %s`
+	copySQLFmt      string = `insert into %s(%s) select %s from %s`
+	tmpTableNameFmt string = `_carpenter_%s`
+
+	autoIncrementRegexp = regexp.MustCompile(`(?i)\bautoincrement\b`)
+)
+
+func ToCreateSQL(table *mysql.Table) []string {
+	return append([]string{toCreateTableSQL(table, table.TableName)}, indicesToCreateSQL(table)...)
+}
+
+// toCreateTableSQL renders the table definition with the specified name.
+// The indices except for the primary key and the unique constraints are created by the independent statements.
+func toCreateTableSQL(table *mysql.Table, name string) string {
+	sqls := make([]string, 0, len(table.Columns)+len(table.ForeignKeys)+1)
+	for _, column := range table.Columns {
+		sqls = append(sqls, columnToSQL(column))
+	}
+	for _, index := range table.Indices {
+		switch {
+		case index.IsPrimaryKey():
+			if hasAutoIncrement(table) {
+				continue
+			}
+			sqls = append(sqls, fmt.Sprintf("primary key (%s)", strings.Join(indexColumnNames(index), ",")))
+		case isAutoIndex(index):
+			sqls = append(sqls, fmt.Sprintf("unique (%s)", strings.Join(indexColumnNames(index), ",")))
+		}
+	}
+	for _, fk := range table.ForeignKeys {
+		sqls = append(sqls, foreignKeyToSQL(fk))
+	}
+	return fmt.Sprintf(createSQLFmt, Quote(name), strings.Join(sqls, ",\n	"))
+}
+
+func ToDropSQL(table *mysql.Table) string {
+	return fmt.Sprintf(dropSQLFmt, Quote(table.TableName))
+}
+
+func ToRenameSQL(table *mysql.Table, name string) string {
+	return fmt.Sprintf(renameSQLFmt, Quote(table.TableName), Quote(name))
+}
+
+// ToAlterSQL renders the differences to the statements.
+// SQLite can only add columns and create or drop indices in place,
+// so the other differences are applied by rebuilding the table.
+// Character sets and partitions are ignored because SQLite does not have them.
+// It returns an error for check constraints, index visibility and column comments,
+// which are neither rendered nor read back on sqlite driver.
+func ToAlterSQL(table *mysql.Table, alter *mysql.Alter) ([]string, error) {
+	if err := validateAlter(table, alter); err != nil {
+		return nil, err
+	}
+	if needsRebuild(alter) {
+		return toRebuildSQL(table, alter), nil
+	}
+	queries := []string{}
+	for _, index := range alter.DropIndices {
+		queries = append(queries, indexToDropSQL(index))
+	}
+	for _, rename := range alter.RenameIndices {
+		queries = append(queries, indexToDropSQL(rename.Index))
+		queries = append(queries, indexToCreateSQL(table, rename.Index.WithKeyName(rename.Name)))
+	}
+	for _, column := range alter.AddColumns {
+		queries = append(queries, fmt.Sprintf(alterSQLFmt, Quote(table.TableName), fmt.Sprintf("add column %s", columnToSQL(column))))
+	}
+	for _, add := range alter.AddIndices {
+		for _, index := range add.Replace {
+			queries = append(queries, indexToDropSQL(index))
+		}
+		for _, index := range add.Indices {
+			queries = append(queries, indexToCreateSQL(table, index))
+		}
+	}
+	return queries, nil
+}
+
+func validateAlter(table *mysql.Table, alter *mysql.Alter) error {
+	unsupported := []string{}
+	if len(alter.DropCheckConstraints) > 0 || len(alter.AddCheckConstraints) > 0 || len(alter.AlterCheckConstraints) > 0 {
+		unsupported = append(unsupported, "check constraints")
+	}
+	if len(alter.AlterIndices) > 0 {
+		unsupported = append(unsupported, "index visibility")
+	}
+	if len(alter.ModifyComments) > 0 {
+		unsupported = append(unsupported, "comments")
+	}
+	if len(unsupported) > 0 {
+		return fmt.Errorf("err: Can not alter %s of table `%s' on sqlite driver", strings.Join(unsupported, ", "), table.TableName)
+	}
+	return nil
+}
+
+func needsRebuild(alter *mysql.Alter) bool {
+	if len(alter.DropForeignKeys) > 0 || len(alter.AddForeignKeys) > 0 ||
+		len(alter.DropColumns) > 0 || len(alter.ChangeColumns) > 0 || len(alter.ModifyColumns) > 0 {
+		return true
+	}
+	for _, column := range alter.AddColumns {
+		if !canAddColumn(column) {
+			return true
+		}
+	}
+	indices := append(mysql.Indices{}, alter.DropIndices...)
+	for _, rename := range alter.RenameIndices {
+		indices = append(indices, rename.Index)
+	}
+	for _, add := range alter.AddIndices {
+		indices = append(indices, add.Replace...)
+		indices = append(indices, add.Indices...)
+	}
+	for _, index := range indices {
+		if index.IsPrimaryKey() || isAutoIndex(index) {
+			return true
+		}
+	}
+	return false
+}
+
+// canAddColumn reports whether the column can be added by alter table statement.
+// SQLite requires a default value for not null columns and does not allow to add key columns.
+func canAddColumn(column *mysql.Column) bool {
+	if column.IsPrimary() || isAutoIncrement(column) {
+		return false
+	}
+	return column.IsNullable() || column.HasDefault()
+}
+
+// toRebuildSQL creates a new table with the new definition, copies the rows,
+// drops the old table and renames the new one as described in https://www.sqlite.org/lang_altertable.html.
+func toRebuildSQL(table *mysql.Table, alter *mysql.Alter) []string {
+	tmpName := fmt.Sprintf(tmpTableNameFmt, table.TableName)
+	oldNames := map[string]string{}
+	for _, change := range alter.ChangeColumns {
+		oldNames[change.Column.ColumnName] = change.OldName
+	}
+	newCols := []string{}
+	oldCols := []string{}
+	for _, column := range table.Columns {
+		if alter.AddColumns.Contains(column) {
+			continue
+		}
+		oldName := column.ColumnName
+		if name, ok := oldNames[column.ColumnName]; ok {
+			oldName = name
+		}
+		newCols = append(newCols, Quote(column.ColumnName))
+		oldCols = append(oldCols, Quote(oldName))
+	}
+	queries := []string{
+		ForeignKeyCheck(false),
+		toCreateTableSQL(table, tmpName),
+	}
+	if len(newCols) > 0 {
+		queries = append(queries, fmt.Sprintf(copySQLFmt, Quote(tmpName), strings.Join(newCols, ","), strings.Join(oldCols, ","), Quote(table.TableName)))
+	}
+	queries = append(queries,
+		fmt.Sprintf(dropSQLFmt, Quote(table.TableName)),
+		fmt.Sprintf(renameSQLFmt, Quote(tmpName), Quote(table.TableName)),
+	)
+	queries = append(queries, indicesToCreateSQL(table)...)
+	return append(queries, ForeignKeyCheck(true))
+}
+
+func hasAutoIncrement(table *mysql.Table) bool {
+	for _, column := range table.Columns {
+		if isAutoIncrement(column) {
+			return true
+		}
+	}
+	return false
+}
+
+func GetTables(db *sql.DB, schema string, tableNames ...string) (mysql.Tables, error) {
+	query := `select name, sql from sqlite_master where type='table' and name not like 'sqlite\_%' escape '\'`
+	if len(tableNames) > 0 {
+		tn := make([]string, 0, len(tableNames))
+		for _, t := range tableNames {
+			tn = append(tn, QuoteString(t))
+		}
+		query = fmt.Sprintf("%s and name in (%s)", query, strings.Join(tn, ","))
+	}
+	query = fmt.Sprintf("%s order by name", query)
+	rows, err := db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("err: db.Query `%s' failed for reason %s", query, err)
+	}
+	defer rows.Close()
+
+	tables := mysql.Tables{}
+	createSQLs := map[string]string{}
+	for rows.Next() {
+		var createSQL string
+		table := &mysql.Table{
+			TableSchema: schema,
+			TableType:   "BASE TABLE",
+		}
+		if err := rows.Scan(&table.TableName, &createSQL); err != nil {
+			return nil, err
+		}
+		createSQLs[table.TableName] = createSQL
+		tables = append(tables, table)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	for i, table := range tables {
+		columns, err := GetColumns(db, schema, table.TableName)
+		if err != nil {
+			return nil, err
+		}
+		// autoincrement is only allowed for the integer primary key
+		if autoIncrementRegexp.MatchString(createSQLs[table.TableName]) {
+			for _, column := range columns {
+				if column.IsPrimary() {
+					column.Extra.Valid = true
+					column.Extra.String = "autoincrement"
+				}
+			}
+		}
+		indices, err := GetIndices(db, table.TableName)
+		if err != nil {
+			return nil, err
+		}
+		fks, err := GetForeignKeys(db, schema, table.TableName)
+		if err != nil {
+			return nil, err
+		}
+		if len(fks) > 0 {
+			tables[i].ForeignKeys = fks
+		}
+		tables[i].Columns = columns
+		tables[i].Indices = indices
+	}
+	return tables, nil
+}
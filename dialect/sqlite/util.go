@@ -0,0 +1,22 @@
+package sqlite
+
+import (
+	"fmt"
+	"strings"
+)
+
+func Quote(name string) string {
+	return fmt.Sprintf(`"%s"`, strings.Replace(name, `"`, `""`, -1))
+}
+
+func QuoteMulti(names []string) []string {
+	res := make([]string, 0, len(names))
+	for _, name := range names {
+		res = append(res, Quote(name))
+	}
+	return res
+}
+
+func QuoteString(value string) string {
+	return fmt.Sprintf("'%s'", strings.Replace(value, "'", "''", -1))
+}
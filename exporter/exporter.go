@@ -1,25 +1,278 @@
 package exporter
 
 import (
+	"bufio"
 	"database/sql"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
 	"strings"
 
+	"github.com/dev-cloverlab/carpenter/dialect"
 	"github.com/dev-cloverlab/carpenter/dialect/mysql"
 )
 
-func Export(db *sql.DB, schema string, tableName string) (string, error) {
-	cnk, err := mysql.GetChunk(db, tableName, nil)
+// NullValue is the unquoted field which represents NULL in the CSV files.
+// The empty field represents the empty string, and the string equal to NullValue is quoted.
+var NullValue = "NULL"
+
+// BinaryEncoding is the encoding of the values of binary columns, which is "hex" or "base64".
+// The values are prefixed by 0x or base64: so that import can decode them.
+var BinaryEncoding = "hex"
+
+var (
+	selectSQLFmt    = "select * from %s"
+	firstPageSQLFmt = "select * from %s order by %s limit %d"
+	nextPageSQLFmt  = "select * from %s where %s > %s order by %s limit %d"
+)
+
+// Export writes the rows of the table to w as CSV defined by RFC 4180.
+// The rows are written as they are read, so the memory usage does not depend on the size of the table.
+// If pageSize is positive, the rows are read by pages ordered by the primary key
+// so that each query finishes without holding a long-running consistent read.
+// The table which has no primary key is read by a query.
+func Export(w io.Writer, db *sql.DB, d dialect.Dialect, schema string, tableName string, pageSize int) error {
+	var keyNames []string
+	if pageSize > 0 {
+		var err error
+		if keyNames, err = getPrimaryKeyNames(db, d, tableName); err != nil {
+			return err
+		}
+	}
+
+	tables, err := d.GetTables(db, schema, tableName)
+	if err != nil {
+		return err
+	}
+	if len(tables) <= 0 {
+		return fmt.Errorf("err: Table `%s' is not found", tableName)
+	}
+	columns := tables[0].Columns
+
+	cw := newCSVWriter(w)
+	table := d.Quote(tableName)
+	if len(keyNames) <= 0 {
+		if _, _, err := writeRows(cw, db, columns, fmt.Sprintf(selectSQLFmt, table), nil, nil, true); err != nil {
+			return err
+		}
+		return cw.Flush()
+	}
+
+	quoted := make([]string, 0, len(keyNames))
+	placeholders := make([]string, 0, len(keyNames))
+	for i, keyName := range keyNames {
+		quoted = append(quoted, d.Quote(keyName))
+		placeholders = append(placeholders, d.Placeholder(i+1))
+	}
+	orderStr := strings.Join(quoted, ",")
+	keyStr := orderStr
+	placeholderStr := strings.Join(placeholders, ",")
+	if len(keyNames) > 1 {
+		// a composite key is compared as a row constructor like (`a`,`b`) > (?,?)
+		keyStr = fmt.Sprintf("(%s)", keyStr)
+		placeholderStr = fmt.Sprintf("(%s)", placeholderStr)
+	}
+
+	query := fmt.Sprintf(firstPageSQLFmt, table, orderStr, pageSize)
+	var last []interface{}
+	for {
+		cnt, l, err := writeRows(cw, db, columns, query, last, keyNames, last == nil)
+		if err != nil {
+			return err
+		}
+		if cnt < pageSize {
+			break
+		}
+		last = l
+		query = fmt.Sprintf(nextPageSQLFmt, table, keyStr, placeholderStr, orderStr, pageSize)
+	}
+	return cw.Flush()
+}
+
+// writeRows writes the rows returned by the query and returns the number of them and the key values of the last row.
+// The values are converted by the types of the columns.
+func writeRows(w *csvWriter, db *sql.DB, columns mysql.Columns, query string, args []interface{}, keyNames []string, header bool) (int, []interface{}, error) {
+	rows, err := db.Query(query, args...)
 	if err != nil {
+		return 0, nil, err
+	}
+	defer rows.Close()
+	names, err := rows.Columns()
+	if err != nil {
+		return 0, nil, err
+	}
+	columnMap := columns.GroupByColumnName()
+	for _, name := range names {
+		if _, ok := columnMap[name]; !ok {
+			return 0, nil, fmt.Errorf("err: Specified columnName `%s' is not found in the result of %s", name, query)
+		}
+	}
+	keyIdxs := make([]int, 0, len(keyNames))
+	for _, keyName := range keyNames {
+		idx := indexOf(names, keyName)
+		if idx < 0 {
+			return 0, nil, fmt.Errorf("err: Specified columnName `%s' is not found in the result of %s", keyName, query)
+		}
+		keyIdxs = append(keyIdxs, idx)
+	}
+	if header {
+		if err := w.Write(names, nil); err != nil {
+			return 0, nil, err
+		}
+	}
+
+	cnt := 0
+	var last []interface{}
+	holders := make([]interface{}, len(names))
+	holderPtrs := make([]interface{}, len(names))
+	for i := range holders {
+		holderPtrs[i] = &holders[i]
+	}
+	cols := make([]string, len(names))
+	quoted := make([]bool, len(names))
+	for rows.Next() {
+		if err := rows.Scan(holderPtrs...); err != nil {
+			return 0, nil, err
+		}
+		// the raw values are kept to bind them to the next query without the conversion
+		last = make([]interface{}, 0, len(keyIdxs))
+		for _, idx := range keyIdxs {
+			last = append(last, holders[idx])
+		}
+		for i, name := range names {
+			value := columnMap[name].ConvertValue(holders[i])
+			cols[i] = toCSVValue(value)
+			// the value equal to NullValue is quoted so that import does not read it as NULL
+			quoted[i] = value != nil && cols[i] == NullValue
+		}
+		if err := w.Write(cols, quoted); err != nil {
+			return 0, nil, err
+		}
+		cnt++
+	}
+	return cnt, last, rows.Err()
+}
+
+// csvWriter writes records by encoding/csv, except for the record which has only an empty field
+// and the record which has the fields to be quoted.
+// The former is written as a quoted empty string because an empty line is skipped by encoding/csv reader,
+// and the latter is written field by field because encoding/csv quotes the fields only when it is needed.
+type csvWriter struct {
+	bw *bufio.Writer
+	cw *csv.Writer
+}
+
+func newCSVWriter(w io.Writer) *csvWriter {
+	bw := bufio.NewWriter(w)
+	return &csvWriter{bw: bw, cw: csv.NewWriter(bw)}
+}
+
+func (m *csvWriter) Write(record []string, quoted []bool) error {
+	if len(record) == 1 && record[0] == "" {
+		m.cw.Flush()
+		if err := m.cw.Error(); err != nil {
+			return err
+		}
+		_, err := m.bw.WriteString("\"\"\n")
+		return err
+	}
+	if !hasTrue(quoted) {
+		return m.cw.Write(record)
+	}
+	m.cw.Flush()
+	if err := m.cw.Error(); err != nil {
+		return err
+	}
+	fields := make([]string, 0, len(record))
+	for i, field := range record {
+		if i < len(quoted) && quoted[i] {
+			fields = append(fields, `"`+strings.Replace(field, `"`, `""`, -1)+`"`)
+			continue
+		}
+		f, err := encodeField(field)
+		if err != nil {
+			return err
+		}
+		fields = append(fields, f)
+	}
+	_, err := m.bw.WriteString(strings.Join(fields, ",") + "\n")
+	return err
+}
+
+func (m *csvWriter) Flush() error {
+	m.cw.Flush()
+	if err := m.cw.Error(); err != nil {
+		return err
+	}
+	return m.bw.Flush()
+}
+
+// encodeField returns the field encoded by encoding/csv, which is quoted only when it is needed.
+func encodeField(field string) (string, error) {
+	buf := &strings.Builder{}
+	cw := csv.NewWriter(buf)
+	if err := cw.Write([]string{field}); err != nil {
 		return "", err
 	}
-	csv := make([]string, 0, len(cnk.Seeds)+1)
-	csv = append(csv, strings.Join(cnk.ColumnNames, ","))
-	for _, seed := range cnk.Seeds {
-		cols := make([]string, 0, len(cnk.ColumnNames))
-		for i := range seed.ColumnData {
-			cols = append(cols, seed.ToColumnValue(i))
+	cw.Flush()
+	return strings.TrimSuffix(buf.String(), "\n"), cw.Error()
+}
+
+func hasTrue(values []bool) bool {
+	for _, v := range values {
+		if v {
+			return true
+		}
+	}
+	return false
+}
+
+func toCSVValue(data interface{}) string {
+	switch v := data.(type) {
+	case nil:
+		return NullValue
+	case string:
+		return v
+	case []byte:
+		return mysql.EncodeBinary(v, BinaryEncoding)
+	case float64:
+		// avoid the exponent format like 1e+06
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+func getPrimaryKeyNames(db *sql.DB, d dialect.Dialect, tableName string) ([]string, error) {
+	indices, err := d.GetIndices(db, tableName)
+	if err != nil {
+		return nil, err
+	}
+	for _, index := range indices {
+		if !index.IsPrimaryKey() {
+			continue
+		}
+		idx := make(mysql.Index, len(index))
+		copy(idx, index)
+		sort.SliceStable(idx, func(i, j int) bool {
+			return idx[i].SeqInIndex < idx[j].SeqInIndex
+		})
+		names := make([]string, 0, len(idx))
+		for _, info := range idx {
+			names = append(names, info.ColumnName)
+		}
+		return names, nil
+	}
+	return nil, nil
+}
+
+func indexOf(names []string, name string) int {
+	for i, n := range names {
+		if n == name {
+			return i
 		}
-		csv = append(csv, strings.Join(cols, ","))
 	}
-	return strings.Join(csv, "\n"), nil
+	return -1
 }
@@ -0,0 +1,84 @@
+package exporter
+
+import (
+	"bytes"
+	"database/sql"
+	"testing"
+
+	"github.com/dev-cloverlab/carpenter/dialect/sqlite"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func TestExport(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+	for _, q := range []string{
+		`create table "user_item" ("user_id" integer not null, "item_id" integer not null, "name" text, primary key ("user_id", "item_id"))`,
+		`insert into "user_item" values (2, 1, 'c'), (1, 2, 'b'), (1, 1, 'a'), (3, 1, null), (2, 2, 'd')`,
+		`create table "log" ("message" text)`,
+		`insert into "log" values ('foo'), ('bar')`,
+		`create table "empty" ("id" integer primary key)`,
+	} {
+		if _, err := db.Exec(q); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	d := sqlite.Dialect{}
+	tests := []struct {
+		table    string
+		expected string
+	}{
+		{"user_item", "user_id,item_id,name\n1,1,a\n1,2,b\n2,1,c\n2,2,d\n3,1,NULL\n"},
+		{"log", "message\nfoo\nbar\n"},
+		{"empty", "id\n"},
+	}
+	for _, test := range tests {
+		for _, pageSize := range []int{0, 1, 2, 5, 100} {
+			buf := &bytes.Buffer{}
+			if err := Export(buf, db, d, "main", test.table, pageSize); err != nil {
+				t.Fatal(err)
+			}
+			if pageSize == 0 && test.table == "user_item" {
+				// rows are not ordered without pagination
+				continue
+			}
+			if actual := buf.String(); actual != test.expected {
+				t.Errorf("err: unexpected CSV of %s by page size %d.\nexpected %q\nbut actual %q", test.table, pageSize, test.expected, actual)
+			}
+		}
+	}
+}
+
+func TestCSVWriter(t *testing.T) {
+	tests := []struct {
+		record   []string
+		quoted   []bool
+		expected string
+	}{
+		{[]string{"a,b", "c"}, nil, "\"a,b\",c\n"},
+		{[]string{"say \"hi\"", "it's"}, nil, "\"say \"\"hi\"\"\",it's\n"},
+		{[]string{"line1\nline2", ""}, nil, "\"line1\nline2\",\n"},
+		{[]string{" space ", "back\\slash"}, nil, "\" space \",back\\slash\n"},
+		{[]string{""}, nil, "\"\"\n"},
+		{[]string{"NULL", "NULL", "a,b", ""}, []bool{true, false, false, false}, "\"NULL\",NULL,\"a,b\",\n"},
+		{[]string{"NULL"}, []bool{true}, "\"NULL\"\n"},
+	}
+	for _, test := range tests {
+		buf := &bytes.Buffer{}
+		w := newCSVWriter(buf)
+		if err := w.Write(test.record, test.quoted); err != nil {
+			t.Fatal(err)
+		}
+		if err := w.Flush(); err != nil {
+			t.Fatal(err)
+		}
+		if actual := buf.String(); actual != test.expected {
+			t.Errorf("err: unexpected CSV of %q.\nexpected %q\nbut actual %q", test.record, test.expected, actual)
+		}
+	}
+}
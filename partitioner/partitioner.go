@@ -0,0 +1,162 @@
+package partitioner
+
+import (
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dev-cloverlab/carpenter/dialect/mysql"
+)
+
+// Policy is the retention policy of the table partitioned by day.
+// Retention is the number of days to keep the partitions, and the partitions are not dropped when it is 0.
+// Ahead is the number of days after today to create the partitions in advance.
+type Policy struct {
+	Retention int
+	Ahead     int
+}
+
+const (
+	partitionNameFormat = "p20060102"
+	dateFormat          = "2006-01-02"
+	datetimeFormat      = "2006-01-02 15:04:05"
+	maxValue            = "MAXVALUE"
+	secondsPerDay       = 24 * 60 * 60
+	// unixEpochDays is to_days('1970-01-01') of MySQL
+	unixEpochDays = 719528
+)
+
+// Rotate returns the statements to create the daily partitions until Ahead days after today
+// and to drop the partitions which expired the retention.
+// The table should be partitioned by RANGE of to_days or by RANGE COLUMNS of a date or datetime column,
+// and the new partitions are named like p20170101 for the rows of the day.
+//
+// The partition is dropped only when it is strictly older than the retention window,
+// which means that the upper bound of the partition is not after the first day of the window.
+// MAXVALUE partition is never dropped, and the new partitions are made by reorganizing it.
+func Rotate(table *mysql.Table, policy Policy, today time.Time) ([]string, error) {
+	if policy.Retention < 0 || policy.Ahead < 0 {
+		return nil, fmt.Errorf("err: Invalid policy of table `%s', retention and ahead should not be negative", table.TableName)
+	}
+	if len(table.Partitions) <= 0 {
+		return nil, fmt.Errorf("err: Table `%s' is not partitioned", table.TableName)
+	}
+	if err := validatePartitions(table); err != nil {
+		return nil, err
+	}
+	today = toDate(today)
+	groups := table.Partitions.GroupByPartitionName()
+	var maxPartition mysql.Partitions
+	if last := groups[len(groups)-1]; last[0].PartitionDescription.String == maxValue {
+		maxPartition = last
+		groups = groups[:len(groups)-1]
+	}
+
+	alter := &mysql.Alter{}
+	windowStart := today.AddDate(0, 0, -policy.Retention)
+	var next time.Time
+	for i, partitions := range groups {
+		bound, err := parseBound(partitions[0])
+		if err != nil {
+			return nil, err
+		}
+		// the last partition is kept because MySQL can not drop all partitions
+		if policy.Retention > 0 && isExpired(bound, windowStart) && (maxPartition != nil || i < len(groups)-1) {
+			alter.DropPartitions = append(alter.DropPartitions, partitions...)
+		}
+		next = bound
+	}
+	if next.IsZero() {
+		next = today
+	}
+
+	adds := mysql.Partitions{}
+	for end := today.AddDate(0, 0, policy.Ahead+1); next.Before(end); next = next.AddDate(0, 0, 1) {
+		adds = append(adds, makePartition(table.Partitions, next))
+	}
+	if len(adds) > 0 {
+		if maxPartition != nil {
+			into := append(append(mysql.Partitions{}, adds...), maxPartition...)
+			alter.ReorganizePartitions = []*mysql.ReorganizePartition{{Partitions: maxPartition, Into: into}}
+		} else {
+			alter.AddPartitions = adds
+		}
+	}
+	return table.ToAlterSQLs(alter), nil
+}
+
+// isExpired reports whether the partition whose upper bound is the specified one is strictly older than the retention window.
+func isExpired(bound, windowStart time.Time) bool {
+	return !bound.After(windowStart)
+}
+
+func validatePartitions(table *mysql.Table) error {
+	p := table.Partitions[0]
+	if p.IsSubpartitioned() {
+		return fmt.Errorf("err: Subpartitioned table `%s' is not supported", table.TableName)
+	}
+	switch p.PartitionMethod {
+	case mysql.PartitionMethodRangeExpr:
+		if strings.HasPrefix(strings.ToLower(p.PartitionExpression), "to_days(") {
+			return nil
+		}
+	case mysql.PartitionMethodRange:
+		if !strings.Contains(p.PartitionExpression, ",") {
+			return nil
+		}
+	}
+	return fmt.Errorf("err: Table `%s' should be partitioned by RANGE of to_days or by RANGE COLUMNS of a column", table.TableName)
+}
+
+// parseBound returns the upper bound of the partition, which is the first day of the next partition.
+// The description is the number of days of to_days like 736695 or the date like '2017-01-01'.
+func parseBound(p *mysql.Partition) (time.Time, error) {
+	desc := p.PartitionDescription.String
+	if p.PartitionMethod == mysql.PartitionMethodRangeExpr {
+		days, err := strconv.ParseInt(desc, 10, 64)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("err: Invalid description `%s' of partition `%s' for reason %s", desc, p.PartitionName, err)
+		}
+		return time.Unix((days-unixEpochDays)*secondsPerDay, 0).UTC(), nil
+	}
+	value := strings.Trim(desc, "'")
+	for _, format := range []string{dateFormat, datetimeFormat} {
+		if t, err := time.Parse(format, value); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("err: Invalid description `%s' of partition `%s', which should be a date", desc, p.PartitionName)
+}
+
+// makePartition returns the partition for the rows of the day, which is a copy of the first partition.
+// The description of RANGE COLUMNS is written in the same format as the existing one.
+func makePartition(partitions mysql.Partitions, day time.Time) *mysql.Partition {
+	p := *partitions[0]
+	p.PartitionName = day.Format(partitionNameFormat)
+	p.PartitionComment = ""
+	bound := day.AddDate(0, 0, 1)
+	desc := strconv.FormatInt(toDays(bound), 10)
+	if p.PartitionMethod == mysql.PartitionMethodRange {
+		format := dateFormat
+		for _, partition := range partitions {
+			if len(strings.Trim(partition.PartitionDescription.String, "'")) == len(datetimeFormat) {
+				format = datetimeFormat
+				break
+			}
+		}
+		desc = mysql.QuoteString(bound.Format(format))
+	}
+	p.PartitionDescription = mysql.JsonNullString{NullString: sql.NullString{String: desc, Valid: true}}
+	return &p
+}
+
+// toDays returns the number of days since year 0 like to_days function of MySQL.
+func toDays(t time.Time) int64 {
+	return toDate(t).Unix()/secondsPerDay + unixEpochDays
+}
+
+func toDate(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,139 @@
+package partitioner
+
+import (
+	"database/sql"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/dev-cloverlab/carpenter/dialect/mysql"
+)
+
+func makeTable(method, expression string, descriptions ...string) *mysql.Table {
+	table := &mysql.Table{TableName: "access_log", CreateOptions: "partitioned"}
+	for i := 0; i+1 < len(descriptions); i += 2 {
+		table.Partitions = append(table.Partitions, &mysql.Partition{
+			TableName:            "access_log",
+			PartitionName:        descriptions[i],
+			PartitionMethod:      method,
+			PartitionExpression:  expression,
+			PartitionDescription: mysql.JsonNullString{NullString: sql.NullString{String: descriptions[i+1], Valid: true}},
+		})
+	}
+	return table
+}
+
+func TestToDays(t *testing.T) {
+	tests := []struct {
+		date     time.Time
+		expected int64
+	}{
+		{time.Date(1995, 5, 1, 0, 0, 0, 0, time.UTC), 728779},
+		{time.Date(2017, 1, 1, 12, 30, 0, 0, time.UTC), 736695},
+	}
+	for _, test := range tests {
+		if actual := toDays(test.date); actual != test.expected {
+			t.Errorf("err: unexpected to_days of %s, expected %d but actual %d", test.date, test.expected, actual)
+		}
+		p := &mysql.Partition{PartitionMethod: mysql.PartitionMethodRangeExpr, PartitionDescription: mysql.JsonNullString{NullString: sql.NullString{String: "736695", Valid: true}}}
+		if bound, err := parseBound(p); err != nil || !bound.Equal(time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC)) {
+			t.Errorf("err: unexpected bound %s, %v", bound, err)
+		}
+	}
+}
+
+func TestRotate(t *testing.T) {
+	today := time.Date(2017, 1, 10, 15, 0, 0, 0, time.Local)
+	tests := []struct {
+		name     string
+		table    *mysql.Table
+		policy   Policy
+		expected []string
+	}{
+		{
+			"range columns",
+			makeTable(mysql.PartitionMethodRange, "`created_at`",
+				"p20170106", "'2017-01-07'", "p20170107", "'2017-01-08'", "p20170108", "'2017-01-09'", "p20170109", "'2017-01-10'", "p20170110", "'2017-01-11'"),
+			Policy{Retention: 2, Ahead: 1},
+			[]string{
+				"alter table `access_log` drop partition `p20170106`,`p20170107`\n\t",
+				"alter table `access_log` add partition (\n\t\tpartition `p20170111` values less than ('2017-01-12')\n\t)\n\t",
+			},
+		},
+		{
+			"to_days with maxvalue",
+			makeTable(mysql.PartitionMethodRangeExpr, "to_days(`created_at`)",
+				"p20170109", "736704", "p20170110", "736705", "pmax", "MAXVALUE"),
+			Policy{Retention: 0, Ahead: 2},
+			[]string{
+				"alter table `access_log` reorganize partition `pmax` into (\n\t\tpartition `p20170111` values less than (736706),\n\t\tpartition `p20170112` values less than (736707),\n\t\tpartition `pmax` values less than MAXVALUE\n\t)\n\t",
+			},
+		},
+		{
+			"datetime",
+			makeTable(mysql.PartitionMethodRange, "`created_at`", "p20170110", "'2017-01-11 00:00:00'"),
+			Policy{Retention: 30, Ahead: 1},
+			[]string{
+				"alter table `access_log` add partition (\n\t\tpartition `p20170111` values less than ('2017-01-12 00:00:00')\n\t)\n\t",
+			},
+		},
+		{
+			"keep the last partition",
+			makeTable(mysql.PartitionMethodRange, "`created_at`", "p20161230", "'2016-12-31'", "p20161231", "'2017-01-01'"),
+			Policy{Retention: 1, Ahead: 0},
+			[]string{
+				"alter table `access_log` drop partition `p20161230`\n\t",
+				"alter table `access_log` add partition (\n\t\tpartition `p20170101` values less than ('2017-01-02'),\n\t\tpartition `p20170102` values less than ('2017-01-03'),\n\t\tpartition `p20170103` values less than ('2017-01-04'),\n\t\tpartition `p20170104` values less than ('2017-01-05'),\n\t\tpartition `p20170105` values less than ('2017-01-06'),\n\t\tpartition `p20170106` values less than ('2017-01-07'),\n\t\tpartition `p20170107` values less than ('2017-01-08'),\n\t\tpartition `p20170108` values less than ('2017-01-09'),\n\t\tpartition `p20170109` values less than ('2017-01-10'),\n\t\tpartition `p20170110` values less than ('2017-01-11')\n\t)\n\t",
+			},
+		},
+		{
+			"nothing to do",
+			makeTable(mysql.PartitionMethodRange, "`created_at`", "p20170110", "'2017-01-11'", "p20170111", "'2017-01-12'"),
+			Policy{Retention: 7, Ahead: 1},
+			[]string{},
+		},
+	}
+	for _, test := range tests {
+		actual, err := Rotate(test.table, test.policy, today)
+		if err != nil {
+			t.Fatalf("err: %s: %s", test.name, err)
+		}
+		if !reflect.DeepEqual(actual, test.expected) {
+			t.Errorf("err: %s: unexpected SQL.\nexpected %q\nbut actual %q", test.name, test.expected, actual)
+		}
+	}
+}
+
+// TestRotateRetentionWindow checks that only the partitions strictly older than the retention window are dropped.
+// With 3 days of retention on 2017-01-10, the window starts at 2017-01-07,
+// so the partition of 2017-01-06 is dropped but the partition of 2017-01-07 is kept.
+func TestRotateRetentionWindow(t *testing.T) {
+	table := makeTable(mysql.PartitionMethodRange, "`created_at`",
+		"p20170106", "'2017-01-07'", "p20170107", "'2017-01-08'", "p20170110", "'2017-01-11'")
+	actual, err := Rotate(table, Policy{Retention: 3}, time.Date(2017, 1, 10, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{"alter table `access_log` drop partition `p20170106`\n\t"}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("err: unexpected SQL.\nexpected %q\nbut actual %q", expected, actual)
+	}
+}
+
+func TestRotateUnsupportedTable(t *testing.T) {
+	tables := []*mysql.Table{
+		{TableName: "access_log"},
+		makeTable(mysql.PartitionMethodList, "`type`", "p0", "1"),
+		makeTable(mysql.PartitionMethodRangeExpr, "`id`", "p0", "10"),
+		makeTable(mysql.PartitionMethodRange, "`created_at`,`id`", "p0", "'2017-01-01',10"),
+		makeTable(mysql.PartitionMethodRange, "`created_at`", "p0", "'2017-01'"),
+	}
+	for _, table := range tables {
+		if _, err := Rotate(table, Policy{Retention: 7}, time.Now()); err == nil {
+			t.Errorf("err: unsupported table %v is accepted", table.Partitions)
+		}
+	}
+	if _, err := Rotate(makeTable(mysql.PartitionMethodRange, "`created_at`", "p0", "'2017-01-01'"), Policy{Retention: -1}, time.Now()); err == nil {
+		t.Errorf("err: negative retention is accepted")
+	}
+}
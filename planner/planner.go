@@ -0,0 +1,323 @@
+package planner
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/dev-cloverlab/carpenter/dialect/mysql"
+)
+
+const (
+	KindCreate = "create"
+	KindDrop   = "drop"
+	KindAlter  = "alter"
+	KindData   = "data"
+
+	versionHeader  = "-- carpenter plan: "
+	checksumHeader = "-- schema checksum: "
+	tableHeader    = "-- table: "
+	changeHeader   = "-- change: "
+
+	delimiterCommand = "delimiter "
+)
+
+type (
+	// Change is the queries for a table.
+	Change struct {
+		TableName string
+		Kind      string
+		Queries   []string
+	}
+	// Plan is the ordered changes with the checksum of the schema which the changes were made from.
+	Plan struct {
+		Version  string
+		Checksum string
+		Changes  []*Change
+	}
+)
+
+// Checksum returns the hash of the definitions of the objects and the rows of the seeded tables,
+// which does not depend on the order of them.
+func Checksum(schema *mysql.Schema, chunks []*mysql.Chunk) (string, error) {
+	elements := []string{}
+	add := func(kind string, v interface{}) error {
+		buf, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		elements = append(elements, kind+":"+string(buf))
+		return nil
+	}
+	for _, table := range schema.Tables {
+		if err := add("table", table); err != nil {
+			return "", err
+		}
+	}
+	for _, view := range schema.Views {
+		if err := add("view", view); err != nil {
+			return "", err
+		}
+	}
+	for _, trigger := range schema.Triggers {
+		if err := add("trigger", trigger); err != nil {
+			return "", err
+		}
+	}
+	for _, routine := range schema.Routines {
+		if err := add("routine", routine); err != nil {
+			return "", err
+		}
+	}
+	for _, event := range schema.Events {
+		if err := add("event", event); err != nil {
+			return "", err
+		}
+	}
+	for _, cnk := range chunks {
+		if err := add("columns:"+cnk.TableName, cnk.ColumnNames); err != nil {
+			return "", err
+		}
+		for _, seed := range cnk.Seeds {
+			if err := add("row:"+cnk.TableName, seed.ColumnData); err != nil {
+				return "", err
+			}
+		}
+	}
+	// json does not have raw line feeds, so the elements are separated by them
+	sort.Strings(elements)
+	return fmt.Sprintf("sha256:%x", sha256.Sum256([]byte(strings.Join(elements, "\n")))), nil
+}
+
+// DataTableNames returns the names of the tables whose rows are changed by the plan.
+func (m *Plan) DataTableNames() []string {
+	names := []string{}
+	for _, change := range m.Changes {
+		if change.Kind == KindData {
+			names = append(names, change.TableName)
+		}
+	}
+	return names
+}
+
+func (m *Plan) Queries() []string {
+	queries := []string{}
+	for _, change := range m.Changes {
+		queries = append(queries, change.Queries...)
+	}
+	return queries
+}
+
+// Write writes the plan to a file or a file for each table in the directory, and returns the written file names.
+// The file names begin with the version and the sequence number so that sorting them keeps the order of the changes.
+func (m *Plan) Write(dir string, separate bool) ([]string, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("err: os.MkdirAll %s failed for reason %s", dir, err)
+	}
+	files := map[string]string{}
+	names := []string{}
+	if separate {
+		digits := len(strconv.Itoa(len(m.Changes)))
+		for i, change := range m.Changes {
+			name := fmt.Sprintf("%s_%0*d_%s_%s.sql", m.Version, digits, i+1, change.Kind, change.TableName)
+			files[name] = m.header() + change.toSQL()
+			names = append(names, name)
+		}
+	} else if len(m.Changes) > 0 {
+		name := fmt.Sprintf("%s_plan.sql", m.Version)
+		sqls := make([]string, 0, len(m.Changes))
+		for _, change := range m.Changes {
+			sqls = append(sqls, change.toSQL())
+		}
+		files[name] = m.header() + strings.Join(sqls, "\n")
+		names = append(names, name)
+	}
+	for _, name := range names {
+		path := filepath.Join(dir, name)
+		if err := ioutil.WriteFile(path, []byte(files[name]), 0644); err != nil {
+			return nil, fmt.Errorf("err: ioutil.WriteFile %s failed for reason %s", path, err)
+		}
+	}
+	return names, nil
+}
+
+func (m *Plan) header() string {
+	return fmt.Sprintf("%s%s\n%s%s\n", versionHeader, m.Version, checksumHeader, m.Checksum)
+}
+
+func (m *Change) toSQL() string {
+	sqls := []string{fmt.Sprintf("\n%s%s\n%s%s\n", tableHeader, m.TableName, changeHeader, m.Kind)}
+	for _, query := range m.Queries {
+		if !strings.Contains(query, ";") {
+			sqls = append(sqls, query+";\n")
+			continue
+		}
+		// the compound statements like the bodies of triggers have semicolons,
+		// so they are terminated by another delimiter in the way of mysql command
+		delimiter := "$$"
+		for strings.Contains(query, delimiter) {
+			delimiter += "$"
+		}
+		sqls = append(sqls, fmt.Sprintf("%s%s\n%s%s\n%s;\n", delimiterCommand, delimiter, query, delimiter, delimiterCommand))
+	}
+	return strings.Join(sqls, "")
+}
+
+// Read reads the plan from the specified file or the sql files in the specified directory.
+// The directory must have only one plan.
+func Read(path string, backslashEscape bool) (*Plan, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("err: os.Stat %s failed for reason %s", path, err)
+	}
+	files := []string{path}
+	if info.IsDir() {
+		if files, err = filepath.Glob(filepath.Join(path, "*.sql")); err != nil {
+			return nil, err
+		}
+		sort.Strings(files)
+	}
+	if len(files) <= 0 {
+		return nil, fmt.Errorf("err: No sql files found in %s", path)
+	}
+	plan := &Plan{}
+	for _, file := range files {
+		p, err := readFile(file, backslashEscape)
+		if err != nil {
+			return nil, err
+		}
+		if plan.Version == "" {
+			plan.Version = p.Version
+			plan.Checksum = p.Checksum
+		}
+		if plan.Version != p.Version || plan.Checksum != p.Checksum {
+			return nil, fmt.Errorf("err: %s is a part of another plan %s", file, p.Version)
+		}
+		plan.Changes = append(plan.Changes, p.Changes...)
+	}
+	return plan, nil
+}
+
+func readFile(file string, backslashEscape bool) (*Plan, error) {
+	fp, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer fp.Close()
+
+	plan := &Plan{}
+	var change *Change
+	bodies := []string{}
+	scanner := bufio.NewScanner(fp)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case plan.Version == "" && strings.HasPrefix(line, versionHeader):
+			plan.Version = strings.TrimPrefix(line, versionHeader)
+		case plan.Checksum == "" && strings.HasPrefix(line, checksumHeader):
+			plan.Checksum = strings.TrimPrefix(line, checksumHeader)
+		case strings.HasPrefix(line, tableHeader):
+			if change != nil {
+				change.Queries = SplitStatements(strings.Join(bodies, "\n"), backslashEscape)
+			}
+			change = &Change{TableName: strings.TrimPrefix(line, tableHeader)}
+			plan.Changes = append(plan.Changes, change)
+			bodies = []string{}
+		case change != nil && change.Kind == "" && strings.HasPrefix(line, changeHeader):
+			change.Kind = strings.TrimPrefix(line, changeHeader)
+		case change != nil:
+			bodies = append(bodies, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("err: reading %s failed for reason %s", file, err)
+	}
+	if change != nil {
+		change.Queries = SplitStatements(strings.Join(bodies, "\n"), backslashEscape)
+	}
+	if plan.Version == "" || plan.Checksum == "" {
+		return nil, fmt.Errorf("err: %s is not a plan file", file)
+	}
+	return plan, nil
+}
+
+// SplitStatements splits the sql by semicolons except for the ones in the quoted strings and the comments.
+// The delimiter is changed by the line like "delimiter $$" as mysql command does.
+// backslashEscape has to be true for MySQL which escapes the quotes by backslashes in strings.
+func SplitStatements(sql string, backslashEscape bool) []string {
+	statements := []string{}
+	delimiter := ";"
+	var quote byte
+	inComment := false
+	start := 0
+	// the special characters are ASCII, so the bytes are scanned to keep any bytes in the strings
+	for i := 0; i < len(sql); i++ {
+		r := sql[i]
+		switch {
+		case inComment:
+			if r == '\n' {
+				inComment = false
+			}
+		case quote != 0:
+			if r == '\\' && backslashEscape && quote != '`' {
+				i++
+			} else if r == quote {
+				if i+1 < len(sql) && sql[i+1] == quote {
+					i++
+				} else {
+					quote = 0
+				}
+			}
+		case (i == 0 || sql[i-1] == '\n') && isDelimiterCommand(sql[i:]):
+			statements = appendStatement(statements, sql[start:i])
+			end := strings.IndexByte(sql[i:], '\n')
+			if end < 0 {
+				end = len(sql) - i
+			}
+			delimiter = strings.Fields(sql[i : i+end])[1]
+			i += end
+			start = i
+		case r == '\'' || r == '"' || r == '`':
+			quote = r
+		case r == '-' && i+1 < len(sql) && sql[i+1] == '-':
+			inComment = true
+		case strings.HasPrefix(sql[i:], delimiter):
+			statements = appendStatement(statements, sql[start:i])
+			i += len(delimiter) - 1
+			start = i + 1
+		}
+	}
+	return appendStatement(statements, sql[start:])
+}
+
+// isDelimiterCommand reports whether the line is the command to change the delimiter like "delimiter $$".
+func isDelimiterCommand(line string) bool {
+	if end := strings.IndexByte(line, '\n'); end >= 0 {
+		line = line[:end]
+	}
+	if len(line) < len(delimiterCommand) || !strings.EqualFold(line[:len(delimiterCommand)], delimiterCommand) {
+		return false
+	}
+	return len(strings.Fields(line)) == 2
+}
+
+// appendStatement appends the statement without the blank and comment lines before it.
+func appendStatement(statements []string, statement string) []string {
+	lines := strings.Split(statement, "\n")
+	for len(lines) > 0 && (strings.HasPrefix(strings.TrimSpace(lines[0]), "--") || strings.TrimSpace(lines[0]) == "") {
+		lines = lines[1:]
+	}
+	statement = strings.Join(lines, "\n")
+	if strings.TrimSpace(statement) == "" {
+		return statements
+	}
+	return append(statements, statement)
+}
@@ -0,0 +1,179 @@
+package planner
+
+import (
+	"io/ioutil"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/dev-cloverlab/carpenter/dialect/mysql"
+)
+
+func makePlan() *Plan {
+	return &Plan{
+		Version:  "20170101000000",
+		Checksum: "sha256:0123",
+		Changes: []*Change{
+			{
+				TableName: "user",
+				Kind:      KindCreate,
+				Queries: []string{
+					"create table if not exists `user` (\n\t`id` int(10) not null,\n\tprimary key (`id`)\n) engine=InnoDB default charset=utf8 ",
+				},
+			},
+			{
+				TableName: "item",
+				Kind:      KindAlter,
+				Queries: []string{
+					"alter table `item` drop foreign key `fk_user`",
+					"alter table `item` add `name` varchar(32) not null default \"\" comment \"it's; -- name\" after `id`",
+				},
+			},
+			{
+				TableName: "user",
+				Kind:      KindData,
+				Queries: []string{
+					"replace into `user`(`id`,`name`)\nvalues\n(1,\"a;\\\"b\"),\n(2,\"-- table: x\")",
+				},
+			},
+			{
+				TableName: "user_log",
+				Kind:      KindCreate,
+				Queries: []string{
+					"create trigger `user_log` after insert on `user` for each row begin\n\tinsert into `log` values (new.`id`, '$$;');\n\tset @x = 1;\nend",
+				},
+			},
+		},
+	}
+}
+
+func TestWriteAndRead(t *testing.T) {
+	for _, separate := range []bool{false, true} {
+		dir, err := ioutil.TempDir("", "carpenter")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer os.RemoveAll(dir)
+
+		plan := makePlan()
+		names, err := plan.Write(dir, separate)
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected := []string{"20170101000000_plan.sql"}
+		if separate {
+			expected = []string{
+				"20170101000000_1_create_user.sql",
+				"20170101000000_2_alter_item.sql",
+				"20170101000000_3_data_user.sql",
+				"20170101000000_4_create_user_log.sql",
+			}
+		}
+		if !reflect.DeepEqual(names, expected) {
+			t.Errorf("err: unexpected file names %v", names)
+		}
+		actual, err := Read(dir, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(actual, plan) {
+			t.Errorf("err: unexpected plan. separate %v\nexpected %+v\nbut actual %+v", separate, plan.Queries(), actual.Queries())
+		}
+	}
+}
+
+func TestReadAnotherPlan(t *testing.T) {
+	dir, err := ioutil.TempDir("", "carpenter")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	plan := makePlan()
+	if _, err := plan.Write(dir, false); err != nil {
+		t.Fatal(err)
+	}
+	plan.Version = "20170102000000"
+	if _, err := plan.Write(dir, false); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Read(dir, true); err == nil {
+		t.Error("err: Read must fail for the directory which has several plans")
+	}
+}
+
+func TestSplitStatements(t *testing.T) {
+	sql := "-- comment\nselect 'a;''b';\nselect \"c\\\";\" from `d;`;\n\n  -- only comment;\n"
+	expected := []string{"select 'a;''b'", "select \"c\\\";\" from `d;`"}
+	if actual := SplitStatements(sql, true); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("err: unexpected statements %q", actual)
+	}
+	// backslash is not an escape character in the standard strings
+	sql = "select 'a\\';select 'b'"
+	expected = []string{"select 'a\\'", "select 'b'"}
+	if actual := SplitStatements(sql, false); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("err: unexpected statements %q", actual)
+	}
+}
+
+func TestSplitStatementsWithDelimiter(t *testing.T) {
+	sql := "select 1;\ndelimiter $$\ncreate procedure `p`()\nbegin\n\tselect ';';\n\tselect 2;\nend$$\nDELIMITER ;\nselect 3;\n"
+	expected := []string{"select 1", "create procedure `p`()\nbegin\n\tselect ';';\n\tselect 2;\nend", "select 3"}
+	if actual := SplitStatements(sql, true); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("err: unexpected statements %q", actual)
+	}
+}
+
+func TestChecksum(t *testing.T) {
+	a := &mysql.Table{TableName: "a"}
+	b := &mysql.Table{TableName: "b"}
+	view := &mysql.View{TableName: "v", ViewDefinition: "select 1"}
+	trigger := &mysql.Trigger{TriggerName: "t", ActionStatement: "set new.id = 1"}
+	cnk := &mysql.Chunk{TableName: "a", ColumnNames: []string{"id"}, Seeds: mysql.Seeds{{ColumnData: []interface{}{1}}, {ColumnData: []interface{}{2}}}}
+	c1, err := Checksum(&mysql.Schema{Tables: mysql.Tables{a, b}, Views: mysql.Views{view}, Triggers: mysql.Triggers{trigger}}, []*mysql.Chunk{cnk})
+	if err != nil {
+		t.Fatal(err)
+	}
+	reversed := &mysql.Chunk{TableName: "a", ColumnNames: []string{"id"}, Seeds: mysql.Seeds{cnk.Seeds[1], cnk.Seeds[0]}}
+	c2, err := Checksum(&mysql.Schema{Tables: mysql.Tables{b, a}, Views: mysql.Views{view}, Triggers: mysql.Triggers{trigger}}, []*mysql.Chunk{reversed})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c1 != c2 {
+		t.Errorf("err: checksum depends on the order of tables and rows %s %s", c1, c2)
+	}
+
+	changes := []func(){
+		func() { b.TableComment = "changed" },
+		func() { view.ViewDefinition = "select 2" },
+		func() { trigger.ActionStatement = "set new.id = 2" },
+		func() { cnk.Seeds[0].ColumnData[0] = 3 },
+	}
+	prev := c1
+	for i, change := range changes {
+		change()
+		c, err := Checksum(&mysql.Schema{Tables: mysql.Tables{a, b}, Views: mysql.Views{view}, Triggers: mysql.Triggers{trigger}}, []*mysql.Chunk{cnk})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if c == prev {
+			t.Errorf("err: checksum is not changed by change %d %s", i, c)
+		}
+		prev = c
+	}
+}
+
+func FuzzSplitStatements(f *testing.F) {
+	for _, s := range []string{"", "it's; -- x", `C:\`, "a\nb;", `\';select 1;`, "/* */", "\x9e"} {
+		f.Add(s)
+	}
+	f.Fuzz(func(t *testing.T, s string) {
+		cnk := &mysql.Chunk{TableName: "t", ColumnNames: []string{"v"}, Seeds: mysql.Seeds{{ColumnData: []interface{}{s}}}}
+		queries := append(cnk.ToInsertSQL(), cnk.ToReplaceSQL()...)
+		sql := strings.Join(queries, ";\n") + ";\n"
+		if actual := SplitStatements(sql, true); !reflect.DeepEqual(actual, queries) {
+			t.Fatalf("err: unexpected statements.\nexpected %q\nbut actual %q", queries, actual)
+		}
+	})
+}
@@ -4,40 +4,49 @@ import (
 	"database/sql"
 	"fmt"
 	"reflect"
+	"sort"
 
+	"github.com/dev-cloverlab/carpenter/dialect"
 	"github.com/dev-cloverlab/carpenter/dialect/mysql"
 )
 
-var (
-	defaultComparisonColumnName string = "id"
-)
-
-func Seed(db *sql.DB, old, new *mysql.Chunk, compColName *string) (queries []string, err error) {
+// Seed compares the rows by the values of keyNames.
+// When keyNames is empty, the primary key or the first unique key of the table is used.
+func Seed(db *sql.DB, d dialect.Dialect, old, new *mysql.Chunk, keyNames []string) (queries []string, err error) {
 	if old == nil && new == nil {
 		return []string{}, fmt.Errorf("err: Both pointer of the specified new and old is nil.")
 	}
 	if reflect.DeepEqual(old, new) {
 		return queries, nil
 	}
-	ccName := defaultComparisonColumnName
-	if compColName != nil {
-		ccName = *compColName
+	ccName := keyNames
+	if len(ccName) <= 0 {
+		tableName := old.TableName
+		if new != nil {
+			tableName = new.TableName
+		}
+		if ccName, err = GetKeyColumnNames(db, d, tableName); err != nil {
+			return []string{}, err
+		}
+		if len(ccName) <= 0 {
+			return []string{}, fmt.Errorf("err: Table `%s' has neither the specified key nor primary key", tableName)
+		}
 	}
-	if q := willTruncate(old, new); len(q) > 0 {
+	if q := willDeleteAll(d, old, new); len(q) > 0 {
 		queries = append(queries, q)
 	} else {
-		if q, err := willDelete(old, new, ccName); err != nil {
+		if q, err := willDelete(d, old, new, ccName); err != nil {
 			return []string{}, err
 		} else if len(q) > 0 {
 			queries = append(queries, q...)
 		}
 	}
-	if q, err := willReplace(old, new, ccName); err != nil {
+	if q, err := willReplace(d, old, new, ccName); err != nil {
 		return []string{}, err
 	} else if len(q) > 0 {
 		queries = append(queries, q...)
 	}
-	if q, err := willInsert(old, new, ccName); err != nil {
+	if q, err := willInsert(d, old, new, ccName); err != nil {
 		return []string{}, err
 	} else if len(q) > 0 {
 		queries = append(queries, q...)
@@ -45,103 +54,253 @@ func Seed(db *sql.DB, old, new *mysql.Chunk, compColName *string) (queries []str
 	return queries, nil
 }
 
-func willTruncate(old, new *mysql.Chunk) string {
+func willDeleteAll(d dialect.Dialect, old, new *mysql.Chunk) string {
 	if len(old.Seeds) != 0 && len(new.Seeds) <= 0 {
-		return old.ToTrancateSQL()
+		return d.ToDeleteAllSQL(old)
 	}
 	return ""
 }
 
-func willInsert(old, new *mysql.Chunk, compColName string) ([]string, error) {
+func willInsert(d dialect.Dialect, old, new *mysql.Chunk, compColNames []string) ([]string, error) {
 	if new == nil {
 		return []string{}, nil
 	}
 	if old == nil && new != nil {
-		return new.ToInsertSQL(), nil
+		return d.ToInsertSQL(new), nil
+	}
+	oldMap, err := old.GetSeedGroupByColumns(compColNames)
+	if err != nil {
+		return nil, err
 	}
-	oldMap, err := old.GetSeedGroupBy(compColName)
+	newMap, err := new.GetSeedGroupByColumns(compColNames)
 	if err != nil {
 		return nil, err
 	}
-	newMap, err := new.GetSeedGroupBy(compColName)
+	keys, err := getKeys(new, compColNames)
 	if err != nil {
 		return nil, err
 	}
 	seeds := mysql.Seeds{}
-	for k, seed := range newMap {
+	for _, k := range keys {
 		if _, ok := oldMap[k]; ok {
 			continue
 		}
-		seeds = append(seeds, seed)
+		seeds = append(seeds, newMap[k])
 	}
 	cnk := mysql.Chunk{
 		TableName:   new.TableName,
 		ColumnNames: new.ColumnNames,
 		Seeds:       seeds,
 	}
-	return cnk.ToInsertSQL(), nil
+	return d.ToInsertSQL(&cnk), nil
 }
 
-func willDelete(old, new *mysql.Chunk, compColName string) ([]string, error) {
+func willDelete(d dialect.Dialect, old, new *mysql.Chunk, compColNames []string) ([]string, error) {
 	if old == nil {
 		return []string{}, nil
 	}
 	if old != nil && new == nil {
-		return []string{old.ToTrancateSQL()}, nil
+		return []string{d.ToDeleteAllSQL(old)}, nil
+	}
+	oldMap, err := old.GetSeedGroupByColumns(compColNames)
+	if err != nil {
+		return nil, err
 	}
-	oldMap, err := old.GetSeedGroupBy(compColName)
+	newMap, err := new.GetSeedGroupByColumns(compColNames)
 	if err != nil {
 		return nil, err
 	}
-	newMap, err := new.GetSeedGroupBy(compColName)
+	keys, err := getKeys(old, compColNames)
 	if err != nil {
 		return nil, err
 	}
 	seeds := mysql.Seeds{}
-	for k, seed := range oldMap {
+	for _, k := range keys {
 		if _, ok := newMap[k]; ok {
 			continue
 		}
-		seeds = append(seeds, seed)
+		seeds = append(seeds, oldMap[k])
 	}
 	cnk := mysql.Chunk{
-		TableName:   new.TableName,
-		ColumnNames: new.ColumnNames,
+		TableName:   old.TableName,
+		ColumnNames: old.ColumnNames,
 		Seeds:       seeds,
 	}
-	colIdx, err := cnk.GetColumnIndexBy(compColName)
+	colIdxs, err := cnk.GetColumnIndicesBy(compColNames)
 	if err != nil {
 		return nil, err
 	}
-	return cnk.ToDeleteSQL(colIdx), nil
+	return d.ToDeleteSQL(&cnk, colIdxs), nil
 }
 
-func willReplace(old, new *mysql.Chunk, compColName string) ([]string, error) {
+func willReplace(d dialect.Dialect, old, new *mysql.Chunk, compColNames []string) ([]string, error) {
 	if old == nil || new == nil {
 		return []string{}, nil
 	}
-	oldMap, err := old.GetSeedGroupBy(compColName)
+	oldMap, err := old.GetSeedGroupByColumns(compColNames)
+	if err != nil {
+		return nil, err
+	}
+	newMap, err := new.GetSeedGroupByColumns(compColNames)
 	if err != nil {
 		return nil, err
 	}
-	newMap, err := new.GetSeedGroupBy(compColName)
+	keys, err := getKeys(new, compColNames)
 	if err != nil {
 		return nil, err
 	}
 	seeds := mysql.Seeds{}
-	for k, seed := range newMap {
+	for _, k := range keys {
 		if _, ok := oldMap[k]; !ok {
 			continue
 		}
 		if newMap[k].ValueEqual(oldMap[k]) {
 			continue
 		}
-		seeds = append(seeds, seed)
+		seeds = append(seeds, newMap[k])
 	}
 	cnk := mysql.Chunk{
 		TableName:   new.TableName,
 		ColumnNames: new.ColumnNames,
 		Seeds:       seeds,
 	}
-	return cnk.ToReplaceSQL(), nil
+	return d.ToReplaceSQL(&cnk, compColNames), nil
+}
+
+// getKeys returns the values of the comparison columns in the order of the seeds without duplication,
+// so that the generated queries do not depend on the order of map iteration.
+func getKeys(cnk *mysql.Chunk, compColNames []string) ([]string, error) {
+	colIdxs, err := cnk.GetColumnIndicesBy(compColNames)
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]string, 0, len(cnk.Seeds))
+	seen := make(map[string]struct{}, len(cnk.Seeds))
+	for _, seed := range cnk.Seeds {
+		if len(seed.ColumnData) <= 0 {
+			continue
+		}
+		k := seed.ToTupleValue(colIdxs...)
+		if _, ok := seen[k]; ok {
+			continue
+		}
+		seen[k] = struct{}{}
+		keys = append(keys, k)
+	}
+	return keys, nil
+}
+
+// GetKeyColumnNames returns the columns of the primary key, or the first unique key when the table has no primary key.
+// It returns nil when the table has neither of them.
+func GetKeyColumnNames(db *sql.DB, d dialect.Dialect, table string) ([]string, error) {
+	indices, err := d.GetIndices(db, table)
+	if err != nil {
+		return nil, err
+	}
+	indicesMap := indices.GroupByKeyName()
+	var unique mysql.Index
+	for _, keyName := range indices.GetSortedKeys() {
+		index := indicesMap[keyName][0]
+		if index.IsPrimaryKey() {
+			return getIndexColumnNames(index), nil
+		}
+		if unique == nil && index.IsUniqueKey() && !index.HasExpression() {
+			unique = index
+		}
+	}
+	if unique != nil {
+		return getIndexColumnNames(unique), nil
+	}
+	return nil, nil
+}
+
+// IsUniqueKeyColumnNames reports whether the columns are the primary key or a unique key of the table regardless of the order,
+// so that the rows can be replaced or upserted by them.
+func IsUniqueKeyColumnNames(db *sql.DB, d dialect.Dialect, table string, columnNames []string) (bool, error) {
+	indices, err := d.GetIndices(db, table)
+	if err != nil {
+		return false, err
+	}
+	expected := append([]string{}, columnNames...)
+	sort.Strings(expected)
+	indicesMap := indices.GroupByKeyName()
+	for _, keyName := range indices.GetSortedKeys() {
+		index := indicesMap[keyName][0]
+		if !index.IsPrimaryKey() && (!index.IsUniqueKey() || index.HasExpression()) {
+			continue
+		}
+		names := getIndexColumnNames(index)
+		sort.Strings(names)
+		if reflect.DeepEqual(names, expected) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func getIndexColumnNames(index mysql.Index) []string {
+	idx := make(mysql.Index, len(index))
+	copy(idx, index)
+	sort.SliceStable(idx, func(i, j int) bool {
+		return idx[i].SeqInIndex < idx[j].SeqInIndex
+	})
+	names := make([]string, 0, len(idx))
+	for _, info := range idx {
+		names = append(names, info.ColumnName)
+	}
+	return names
+}
+
+// GetChunk returns the rows of the table whose values are converted by the types of the columns,
+// so that they can be compared with the values parsed from CSV files by mysql.Column.ParseValue.
+func GetChunk(db *sql.DB, d dialect.Dialect, table *mysql.Table) (*mysql.Chunk, error) {
+	rows, err := db.Query(fmt.Sprintf("select * from %s", d.Quote(table.TableName)))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	names, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	columnMap := table.Columns.GroupByColumnName()
+	columns := make(mysql.Columns, 0, len(names))
+	columnNames := make([]string, 0, len(names))
+	for _, name := range names {
+		column, ok := columnMap[name]
+		if !ok {
+			return nil, fmt.Errorf("err: Specified columnName `%s' is not found in this table %s", name, table.TableName)
+		}
+		columns = append(columns, column)
+		if !column.IsGenerated() {
+			columnNames = append(columnNames, name)
+		}
+	}
+	// the values of the generated columns are excluded because they can not be written
+	cnk := &mysql.Chunk{
+		TableName:   table.TableName,
+		ColumnNames: columnNames,
+		Seeds:       mysql.Seeds{},
+	}
+	holders := make([]interface{}, len(names))
+	holderPtrs := make([]interface{}, len(names))
+	for i := range holders {
+		holderPtrs[i] = &holders[i]
+	}
+	for rows.Next() {
+		if err := rows.Scan(holderPtrs...); err != nil {
+			return nil, err
+		}
+		columnData := make([]interface{}, 0, len(columnNames))
+		for i, column := range columns {
+			if column.IsGenerated() {
+				continue
+			}
+			columnData = append(columnData, column.ConvertValue(holders[i]))
+		}
+		cnk.Seeds = append(cnk.Seeds, mysql.Seed{
+			ColumnData: columnData,
+		})
+	}
+	return cnk, rows.Err()
 }
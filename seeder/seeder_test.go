@@ -41,7 +41,7 @@ func TestMain(m *testing.M) {
 	if err != nil {
 		panic(err)
 	}
-	createSQL, err := builder.Build(db, nil, new[0], true)
+	createSQL, err := builder.Build(db, mysql.Dialect{}, nil, new[0], true, false)
 	if err != nil {
 		panic(err)
 	}
@@ -59,25 +59,20 @@ func TestMain(m *testing.M) {
 }
 
 func TestInsert(t *testing.T) {
-	colName := "int"
-	oldChunk, err := mysql.GetChunk(db, "seed_test", &colName)
-	if err != nil {
-		t.Fatal(err)
-	}
+	oldChunk := getChunk(t, "seed_test")
 	now := time.Now().Format(mysql.TimeFmt)
 	newChunk := makeChunk("seed_test", oldChunk.ColumnNames, mysql.Seeds{
-		makeSeed([]interface{}{float64(10), "stringA", now, nil}),
-		makeSeed([]interface{}{float64(20), "stringB", now, nil}),
+		makeSeed([]interface{}{int64(10), "stringA", now, nil}),
+		makeSeed([]interface{}{int64(20), "stringB", now, nil}),
 	})
 
 	expected := []string{
 		"insert into `seed_test`(`int`,`string`,`time`,`null`)\n" +
 			"values\n" +
-			fmt.Sprintf("(10,\"stringA\",\"%v\",null),\n", now) +
-			fmt.Sprintf("(20,\"stringB\",\"%v\",null)", now),
+			fmt.Sprintf("(10,'stringA','%v',null),\n", now) +
+			fmt.Sprintf("(20,'stringB','%v',null)", now),
 	}
-	compString := "int"
-	actual, err := Seed(db, oldChunk, newChunk, &compString)
+	actual, err := Seed(db, mysql.Dialect{}, oldChunk, newChunk, []string{"int"})
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -92,24 +87,19 @@ func TestInsert(t *testing.T) {
 }
 
 func TestReplace(t *testing.T) {
-	colName := "int"
-	oldChunk, err := mysql.GetChunk(db, "seed_test", &colName)
-	if err != nil {
-		t.Fatal(err)
-	}
+	oldChunk := getChunk(t, "seed_test")
 	now := time.Now().Format(mysql.TimeFmt)
 	newChunk := makeChunk("seed_test", oldChunk.ColumnNames, mysql.Seeds{
-		makeSeed([]interface{}{float64(10), "stringC", now, nil}),
-		makeSeed([]interface{}{float64(20), "stringB", now, nil}),
+		makeSeed([]interface{}{int64(10), "stringC", now, nil}),
+		makeSeed([]interface{}{int64(20), "stringB", now, nil}),
 	})
 
 	expected := []string{
 		"replace into `seed_test`(`int`,`string`,`time`,`null`)\n" +
 			"values\n" +
-			fmt.Sprintf("(10,\"stringC\",\"%v\",null)", now),
+			fmt.Sprintf("(10,'stringC','%v',null)", now),
 	}
-	compString := "int"
-	actual, err := Seed(db, oldChunk, newChunk, &compString)
+	actual, err := Seed(db, mysql.Dialect{}, oldChunk, newChunk, []string{"int"})
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -124,14 +114,10 @@ func TestReplace(t *testing.T) {
 }
 
 func TestDelete(t *testing.T) {
-	colName := "int"
-	oldChunk, err := mysql.GetChunk(db, "seed_test", &colName)
-	if err != nil {
-		t.Fatal(err)
-	}
+	oldChunk := getChunk(t, "seed_test")
 	now := time.Now().Format(mysql.TimeFmt)
 	newChunk := makeChunk("seed_test", oldChunk.ColumnNames, mysql.Seeds{
-		makeSeed([]interface{}{float64(20), "stringB", now, nil}),
+		makeSeed([]interface{}{int64(20), "stringB", now, nil}),
 	})
 
 	expected := []string{
@@ -139,8 +125,7 @@ func TestDelete(t *testing.T) {
 			"10\n" +
 			")",
 	}
-	compString := "int"
-	actual, err := Seed(db, oldChunk, newChunk, &compString)
+	actual, err := Seed(db, mysql.Dialect{}, oldChunk, newChunk, []string{"int"})
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -155,18 +140,58 @@ func TestDelete(t *testing.T) {
 }
 
 func TestTruncate(t *testing.T) {
-	colName := "int"
-	oldChunk, err := mysql.GetChunk(db, "seed_test", &colName)
+	oldChunk := getChunk(t, "seed_test")
+	newChunk := makeChunk("seed_test", oldChunk.ColumnNames, mysql.Seeds{})
+
+	expected := []string{
+		"delete from `seed_test`",
+	}
+	actual, err := Seed(db, mysql.Dialect{}, oldChunk, newChunk, []string{"int"})
 	if err != nil {
 		t.Fatal(err)
 	}
-	newChunk := makeChunk("seed_test", oldChunk.ColumnNames, mysql.Seeds{})
+	if !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("err: create: unexpected SQL returned.\nactual:\n%s\nexpected:\n%s\n", actual, expected)
+	}
+	for _, sql := range actual {
+		if _, err := db.Exec(sql); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestCompositeKey(t *testing.T) {
+	queries := []string{
+		"create table `seed_composite_test` (`user_id` int not null, `item_id` int not null, `count` int not null, primary key (`user_id`, `item_id`))",
+		"insert into `seed_composite_test` values (1, 1, 1), (1, 2, 1), (2, 1, 1)",
+	}
+	for _, sql := range queries {
+		if _, err := db.Exec(sql); err != nil {
+			t.Fatal(err)
+		}
+	}
+	defer db.Exec("drop table if exists `seed_composite_test`")
+
+	oldChunk := getChunk(t, "seed_composite_test")
+	newChunk := makeChunk("seed_composite_test", oldChunk.ColumnNames, mysql.Seeds{
+		makeSeed([]interface{}{int64(1), int64(1), int64(1)}),
+		makeSeed([]interface{}{int64(2), int64(1), int64(5)}),
+		makeSeed([]interface{}{int64(2), int64(2), int64(1)}),
+	})
 
 	expected := []string{
-		"truncate table `seed_test`",
+		"delete from `seed_composite_test` where (`user_id`,`item_id`) in (\n" +
+			"(1,2)\n" +
+			")",
+		"replace into `seed_composite_test`(`user_id`,`item_id`,`count`)\n" +
+			"values\n" +
+			"(2,1,5)",
+		"insert into `seed_composite_test`(`user_id`,`item_id`,`count`)\n" +
+			"values\n" +
+			"(2,2,1)",
 	}
-	compString := "int"
-	actual, err := Seed(db, oldChunk, newChunk, &compString)
+	// the primary key is used when the key columns are not specified
+	actual, err := Seed(db, mysql.Dialect{}, oldChunk, newChunk, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -180,6 +205,18 @@ func TestTruncate(t *testing.T) {
 	}
 }
 
+func getChunk(t *testing.T, tableName string) *mysql.Chunk {
+	tables, err := mysql.GetTables(db, schema, tableName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cnk, err := GetChunk(db, mysql.Dialect{}, tables[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cnk
+}
+
 func makeSeed(columnData []interface{}) mysql.Seed {
 	return mysql.Seed{
 		ColumnData: columnData,